@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/morfo-si/owlify/pkg/config"
+	"github.com/morfo-si/owlify/pkg/jira"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	noBrowser bool
+	secretKey string
+	removeKey string
+
+	authCmd = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage Jira authentication",
+	}
+
+	authLoginCmd = &cobra.Command{
+		Use:   "login",
+		Short: "Log in to Jira Cloud with OAuth 2.0",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.GetJiraConfig()
+			if cfg.AuthType != config.AuthOAuth {
+				return fmt.Errorf("auth login requires JIRA_AUTH_TYPE=%s", config.AuthOAuth)
+			}
+
+			tokenPath, err := config.OAuthTokenPath()
+			if err != nil {
+				return err
+			}
+
+			oauth, err := oauthConfig(cfg)
+			if err != nil {
+				return err
+			}
+			token, err := oauth.Login(cmd.Context(), func(authURL string) error {
+				fmt.Printf("Open the following URL in your browser to authorize owlify:\n\n  %s\n\n", authURL)
+				if !noBrowser {
+					if err := openBrowser(authURL); err != nil {
+						fmt.Printf("Could not open a browser: %v\n", err)
+					}
+				}
+				fmt.Println("Waiting for authorization...")
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("error logging in: %w", err)
+			}
+
+			// Resolve which Jira site the token is used for
+			resources, err := oauth.AccessibleResources(cmd.Context(), token)
+			if err != nil {
+				return fmt.Errorf("error fetching accessible sites: %w", err)
+			}
+			resource, err := jira.FindResource(resources, cfg.BaseURL)
+			if err != nil {
+				return err
+			}
+			token.CloudID = resource.ID
+			token.SiteURL = resource.URL
+
+			if err := (jira.FileTokenStore{Path: tokenPath}).SaveToken(token); err != nil {
+				return fmt.Errorf("error saving token: %w", err)
+			}
+			fmt.Printf("Logged in to %s\n", resource.URL)
+			return nil
+		},
+	}
+
+	authSetTokenCmd = &cobra.Command{
+		Use:   "set-token",
+		Short: "Store a token in the encrypted credential store",
+		Long: `Store a token, or another secret selected with --key, in the encrypted
+credential store for the selected profile.
+
+The secret is read from standard input, or asked for without echoing it when
+run in a terminal. The store is encrypted with a passphrase, which is asked
+for or taken from OWLIFY_PASSPHRASE. Secrets set in the environment, such as
+JIRA_TOKEN, take precedence over stored ones.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{configAnnotation: configNone},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !config.IsSecret(secretKey) {
+				return fmt.Errorf("--key must be one of token, session_cookie or oauth_client_secret, got %q", secretKey)
+			}
+			file, err := loadConfigFile()
+			if err != nil {
+				return err
+			}
+			name := file.SelectProfile(profile)
+
+			store, err := openCredentialStore()
+			if err != nil {
+				return err
+			}
+			secret, err := readSecret(cmd, fmt.Sprintf("%s for profile %s", strings.ReplaceAll(secretKey, "_", " "), name))
+			if err != nil {
+				return err
+			}
+			if err := store.Set(name, secretKey, secret); err != nil {
+				return err
+			}
+			if err := store.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Stored %s for profile %s in %s\n", secretKey, name, store.Path)
+
+			if _, ok := file.Get(name, secretKey); ok {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Profile %s also holds %s in plain text; remove it with `owlify config unset %s`.\n", name, secretKey, secretKey)
+			}
+			return nil
+		},
+	}
+
+	authRemoveCmd = &cobra.Command{
+		Use:   "remove",
+		Short: "Remove stored secrets of the profile from the credential store",
+		Long: `Remove the secrets stored for the selected profile from the encrypted
+credential store. Only the secret selected with --key is removed when it is
+given, which needs the passphrase; removing every secret of the profile does not.`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{configAnnotation: configNone},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := loadConfigFile()
+			if err != nil {
+				return err
+			}
+			name := file.SelectProfile(profile)
+
+			path, err := config.CredentialsFile()
+			if err != nil {
+				return err
+			}
+			store, err := config.LoadCredentialStore(path)
+			if err != nil {
+				return err
+			}
+			if removeKey != "" && store.HasProfile(name) {
+				pass, err := storePassphrase()
+				if err != nil {
+					return err
+				}
+				if err := store.Unlock(pass); err != nil {
+					return err
+				}
+			}
+
+			removed, err := store.Remove(name, removeKey)
+			if err != nil {
+				return err
+			}
+			if !removed {
+				return fmt.Errorf("no stored secrets for profile %s", name)
+			}
+			if err := store.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed stored secrets of profile %s\n", name)
+			return nil
+		},
+	}
+)
+
+// openCredentialStore loads and unlocks the credential store. A new store
+// asks for its passphrase twice.
+func openCredentialStore() (*config.CredentialStore, error) {
+	path, err := config.CredentialsFile()
+	if err != nil {
+		return nil, err
+	}
+	store, err := config.LoadCredentialStore(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pass, err := storePassphrase()
+	if err != nil {
+		return nil, err
+	}
+	if !store.Exists() && os.Getenv("OWLIFY_PASSPHRASE") == "" {
+		again, err := promptHidden("Repeat the passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if again != pass {
+			return nil, errors.New("the passphrases do not match")
+		}
+	}
+	if err := store.Unlock(pass); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// storePassphrase returns the credential store passphrase from
+// OWLIFY_PASSPHRASE, or asks for it when run in a terminal
+func storePassphrase() (string, error) {
+	if pass := os.Getenv("OWLIFY_PASSPHRASE"); pass != "" {
+		return pass, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("the credential store is encrypted: set OWLIFY_PASSPHRASE or run owlify in a terminal")
+	}
+	return promptHidden("Credential store passphrase: ")
+}
+
+// promptHidden asks for a value on the terminal without echoing it
+func promptHidden(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	value, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("error reading passphrase: %w", err)
+	}
+	return string(value), nil
+}
+
+// readSecret asks for a secret in a terminal, or reads it from standard input
+func readSecret(cmd *cobra.Command, what string) (string, error) {
+	var secret string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		value, err := promptHidden(strings.ToUpper(what[:1]) + what[1:] + ": ")
+		if err != nil {
+			return "", err
+		}
+		secret = value
+	} else {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", fmt.Errorf("error reading %s: %w", what, err)
+		}
+		secret = string(data)
+	}
+	if secret = strings.TrimSpace(secret); secret == "" {
+		return "", fmt.Errorf("no %s given", what)
+	}
+	return secret, nil
+}
+
+// openBrowser opens url in the user's default browser
+func openBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
+
+func init() {
+	authLoginCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Print the authorization URL instead of opening a browser")
+
+	authSetTokenCmd.Flags().StringVar(&secretKey, "key", "token", "Secret to store: token, session_cookie or oauth_client_secret")
+	authRemoveCmd.Flags().StringVar(&removeKey, "key", "", "Only remove this secret instead of every secret of the profile")
+
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authSetTokenCmd)
+	authCmd.AddCommand(authRemoveCmd)
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/morfo-si/owlify/pkg/jira"
 	"github.com/morfo-si/owlify/pkg/reports"
 
 	"github.com/spf13/cobra"
@@ -24,9 +23,13 @@ var (
 			if project == "" {
 				return fmt.Errorf("project is required")
 			}
-			boards, err := jira.FetchBoards(project, jira.JIRAGetRequest)
+			client, err := newJiraClient(cmd.Context())
 			if err != nil {
-				return fmt.Errorf("error fetching boards: %v", err)
+				return err
+			}
+			boards, err := client.FetchBoards(cmd.Context(), project, limit)
+			if err != nil {
+				return fmt.Errorf("error fetching boards: %w", err)
 			}
 			
 			// Handle empty boards result
@@ -40,7 +43,7 @@ var (
 			}
 			
 			if err := reports.GenerateReport(boards, reports.OutputFormat(output)); err != nil {
-				return fmt.Errorf("error generating report: %v", err)
+				return fmt.Errorf("error generating report: %w", err)
 			}
 			return nil
 		},
@@ -49,5 +52,6 @@ var (
 
 func init() {
 	boardListCmd.PersistentFlags().StringVarP(&project, "project", "p", "", "JIRA project key (required)")
+	boardListCmd.Flags().IntVarP(&limit, "limit", "l", 0, "Maximum number of boards to fetch (0 for all)")
 	boardCmd.AddCommand(boardListCmd)
 }
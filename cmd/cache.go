@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morfo-si/owlify/pkg/config"
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect or clear the Jira response cache",
+		// The cache is local, no credentials are needed
+		Annotations: map[string]string{configAnnotation: configOptional},
+	}
+
+	cacheStatsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show how many responses are cached",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := responseCache(config.GetJiraConfig())
+			if err != nil {
+				return err
+			}
+			stats, err := cache.Stats()
+			if err != nil {
+				return err
+			}
+
+			dir, err := config.CacheDir()
+			if err != nil {
+				return err
+			}
+			results := []diagnostic{
+				{"Directory", dir},
+				{"Entries", fmt.Sprint(stats.Entries)},
+				{"Fresh", fmt.Sprint(stats.Fresh)},
+				{"Expired", fmt.Sprint(stats.Expired)},
+				{"Size", formatBytes(stats.Bytes)},
+			}
+			if stats.Entries > 0 {
+				results = append(results,
+					diagnostic{"Oldest", stats.Oldest.Format(time.DateTime)},
+					diagnostic{"Newest", stats.Newest.Format(time.DateTime)},
+				)
+			}
+
+			if err := reports.GenerateReport(results, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cacheClearCmd = &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached responses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := responseCache(config.GetJiraConfig())
+			if err != nil {
+				return err
+			}
+			removed, err := cache.Clear()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed %d cached responses\n", removed)
+			return nil
+		},
+	}
+)
+
+// formatBytes returns n as a human readable size
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
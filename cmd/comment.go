@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/morfo-si/owlify/pkg/jira"
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// visibilityRole and visibilityGroup restrict comments and worklogs
+	visibilityRole  string
+	visibilityGroup string
+
+	commentID       string
+	commentFile     string
+	commentLimit    int
+	commentInEditor bool
+
+	issueCommentCmd = &cobra.Command{
+		Use:   "comment",
+		Short: "List, add, edit and delete the comments of a JIRA issue",
+		Long: `List, add, edit and delete the comments of a JIRA issue.
+
+Comments are written in Markdown. They are converted to Atlassian Document
+Format on JIRA_API_VERSION=3 and to Jira wiki markup on version 2. The body
+is given as argument, read from a file or standard input with --file
+(- reads stdin), or written in $EDITOR with --editor.`,
+	}
+
+	issueCommentListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the comments of a JIRA issue, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			comments, err := client.FetchComments(cmd.Context(), issueKey, commentLimit)
+			if err != nil {
+				return err
+			}
+
+			rows := make([]commentRow, len(comments))
+			for i, comment := range comments {
+				rows[i] = newCommentRow(comment)
+			}
+			if err := reports.GenerateReport(rows, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+
+	issueCommentAddCmd = &cobra.Command{
+		Use:   "add [BODY]",
+		Short: "Add a comment to a JIRA issue",
+		Example: `  owlify issue comment add -k PROJ-1 "Reproduced on **1.2**"
+  owlify issue comment add -k PROJ-1 --role Developers --file notes.md
+  owlify issue comment add -k PROJ-1 --editor`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+			body, err := commentBody(args, "")
+			if err != nil {
+				return err
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			comment, err := client.AddComment(cmd.Context(), issueKey, jira.NewComment{Body: body, Visibility: visibilityFromFlags()})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Added comment %s to issue %s\n", comment.ID, issueKey)
+			return nil
+		},
+	}
+
+	issueCommentEditCmd = &cobra.Command{
+		Use:   "edit [BODY]",
+		Short: "Replace a comment of a JIRA issue",
+		Long: `Replace the body of a comment of a JIRA issue, and its visibility with
+--role or --group.
+
+With --editor the comment is opened in $EDITOR; on JIRA_API_VERSION=3 the
+editor starts with the current comment as Markdown.`,
+		Example: `  owlify issue comment edit -k PROJ-1 --id 10042 "Fixed in **1.3**"
+  owlify issue comment edit -k PROJ-1 --id 10042 --editor`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			// Wiki markup would be mangled if edited as Markdown, so only
+			// ADF comments, which read as Markdown, are edited in place
+			current := ""
+			if commentInEditor && client.APIVersion() == jira.APIVersion3 {
+				comment, err := client.GetComment(cmd.Context(), issueKey, commentID)
+				if err != nil {
+					return err
+				}
+				current = string(comment.Body)
+			}
+			body, err := commentBody(args, current)
+			if err != nil {
+				return err
+			}
+
+			if _, err := client.UpdateComment(cmd.Context(), issueKey, commentID, jira.NewComment{Body: body, Visibility: visibilityFromFlags()}); err != nil {
+				return err
+			}
+			fmt.Printf("Updated comment %s of issue %s\n", commentID, issueKey)
+			return nil
+		},
+	}
+
+	issueCommentDeleteCmd = &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a comment of a JIRA issue",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := client.DeleteComment(cmd.Context(), issueKey, commentID); err != nil {
+				return err
+			}
+			fmt.Printf("Deleted comment %s of issue %s\n", commentID, issueKey)
+			return nil
+		},
+	}
+)
+
+// commentRow is one comment in the report of issue comment list
+type commentRow struct {
+	ID         string `json:"id"`
+	Author     string `json:"author"`
+	Created    string `json:"created"`
+	Updated    string `json:"updated"`
+	Visibility string `json:"visibility"`
+	Body       string `json:"body"`
+}
+
+// newCommentRow returns the report row of comment
+func newCommentRow(comment jira.Comment) commentRow {
+	row := commentRow{
+		ID:         comment.ID,
+		Author:     comment.Author.DisplayName,
+		Created:    formatTime(comment.Created),
+		Visibility: comment.Visibility.String(),
+		Body:       string(comment.Body),
+	}
+	// Only show edits, the update time of unedited comments is the creation time
+	if comment.Updated != nil && (comment.Created == nil || !comment.Updated.Equal(*comment.Created)) {
+		row.Updated = formatTime(comment.Updated)
+	}
+	return row
+}
+
+// formatTime formats a timestamp in local time for reports
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// commentBody returns the body of a comment from args, --file or the
+// editor, which starts with current
+func commentBody(args []string, current string) (string, error) {
+	var body string
+	switch {
+	case len(args) > 0:
+		body = args[0]
+	case commentFile != "":
+		text, err := readInput(commentFile)
+		if err != nil {
+			return "", err
+		}
+		body = text
+	case commentInEditor:
+		text, err := editText(current)
+		if err != nil {
+			return "", err
+		}
+		body = text
+	default:
+		return "", fmt.Errorf("a comment is required: give it as argument, with --file or with --editor")
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("the comment is empty")
+	}
+	return body, nil
+}
+
+// visibilityFromFlags returns the visibility set with --role or --group
+func visibilityFromFlags() *jira.Visibility {
+	switch {
+	case visibilityRole != "":
+		return &jira.Visibility{Type: jira.VisibilityRole, Value: visibilityRole}
+	case visibilityGroup != "":
+		return &jira.Visibility{Type: jira.VisibilityGroup, Value: visibilityGroup}
+	}
+	return nil
+}
+
+func init() {
+	issueCommentListCmd.Flags().IntVarP(&commentLimit, "limit", "l", 0, "Show at most this many comments (default all)")
+
+	for _, cmd := range []*cobra.Command{issueCommentAddCmd, issueCommentEditCmd} {
+		cmd.Flags().StringVar(&commentFile, "file", "", "Read the comment from this file (- reads stdin)")
+		cmd.Flags().BoolVar(&commentInEditor, "editor", false, "Write the comment in $EDITOR")
+		cmd.Flags().StringVar(&visibilityRole, "role", "", "Restrict the comment to the members of this project role")
+		cmd.Flags().StringVar(&visibilityGroup, "group", "", "Restrict the comment to the members of this group")
+		cmd.MarkFlagsMutuallyExclusive("file", "editor")
+		cmd.MarkFlagsMutuallyExclusive("role", "group")
+	}
+	for _, cmd := range []*cobra.Command{issueCommentEditCmd, issueCommentDeleteCmd} {
+		cmd.Flags().StringVar(&commentID, "id", "", "ID of the comment, as shown by issue comment list (required)")
+		_ = cmd.MarkFlagRequired("id")
+	}
+
+	issueCommentCmd.AddCommand(issueCommentListCmd)
+	issueCommentCmd.AddCommand(issueCommentAddCmd)
+	issueCommentCmd.AddCommand(issueCommentEditCmd)
+	issueCommentCmd.AddCommand(issueCommentDeleteCmd)
+	issueCmd.AddCommand(issueCommentCmd)
+}
@@ -0,0 +1,469 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/morfo-si/owlify/pkg/config"
+	"github.com/morfo-si/owlify/pkg/jira"
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// maskedValue replaces secrets in config list output
+const maskedValue = "********"
+
+var (
+	noValidate bool
+
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Manage owlify configuration profiles",
+		Long: `Manage the configuration file and its profiles.
+
+Settings are written to the profile selected with --profile or
+OWLIFY_PROFILE, or to the default profile of the file. The file is created
+in the owlify config directory unless --config or OWLIFY_CONFIG points
+elsewhere, and is only readable by the current user.`,
+		// The configuration may not be complete yet, so it is not loaded
+		Annotations: map[string]string{configAnnotation: configNone},
+	}
+
+	configInitCmd = &cobra.Command{
+		Use:   "init",
+		Short: "Create or update a profile interactively",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := loadConfigFile()
+			if err != nil {
+				return err
+			}
+			return runConfigWizard(cmd, file, newPrompter(cmd.InOrStdin(), cmd.OutOrStdout()))
+		},
+	}
+
+	configGetCmd = &cobra.Command{
+		Use:   "get KEY",
+		Short: "Print the effective value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := strings.ToLower(args[0])
+			if err := config.ValidateKey(key); err != nil {
+				return err
+			}
+			values, err := config.Values(config.Options{ConfigFile: configFile, Profile: profile})
+			if err != nil {
+				return err
+			}
+			for _, value := range values {
+				if value.Key == key {
+					fmt.Fprintln(cmd.OutOrStdout(), value.Value)
+					return nil
+				}
+			}
+			return fmt.Errorf("%s is not set", key)
+		},
+	}
+
+	configSetCmd = &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Store a setting in the profile",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := loadConfigFile()
+			if err != nil {
+				return err
+			}
+			// Secrets given on the command line would end up in plain text
+			if key := strings.ToLower(args[0]); config.IsSecret(key) {
+				return fmt.Errorf("%s is a secret and is not stored in the config file; store it encrypted with `owlify auth set-token --key %s`", key, key)
+			}
+			name := file.SelectProfile(profile)
+			if err := file.Set(name, args[0], args[1]); err != nil {
+				return err
+			}
+			if err := file.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Set %s in profile %s (%s)\n", strings.ToLower(args[0]), name, file.Path)
+			return nil
+		},
+	}
+
+	configUnsetCmd = &cobra.Command{
+		Use:   "unset KEY",
+		Short: "Remove a setting from the profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := loadConfigFile()
+			if err != nil {
+				return err
+			}
+			name := file.SelectProfile(profile)
+			if !file.Unset(name, args[0]) {
+				return fmt.Errorf("%s is not set in profile %s", strings.ToLower(args[0]), name)
+			}
+			if err := file.Save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed %s from profile %s (%s)\n", strings.ToLower(args[0]), name, file.Path)
+			return nil
+		},
+	}
+
+	configListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the effective settings and where they come from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			values, err := config.Values(config.Options{ConfigFile: configFile, Profile: profile})
+			if err != nil {
+				return err
+			}
+			for i, value := range values {
+				if config.IsSecret(value.Key) && value.Value != "" {
+					values[i].Value = maskedValue
+				}
+			}
+			if err := reports.GenerateReport(values, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+
+	configPathCmd = &cobra.Command{
+		Use:   "path",
+		Short: "Print the location of the configuration file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.ConfigFilePath(configFile)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			return nil
+		},
+	}
+
+	configValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check the profiles of the configuration file for errors",
+		Long: `Check the profiles of the configuration file for errors.
+
+Every profile is checked unless one is selected with --profile. Environment
+variables are applied as they would be when running a command.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := loadConfigFile()
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(file.Path); err != nil {
+				return fmt.Errorf("no config file at %s; run `owlify config init` to create one", file.Path)
+			}
+
+			names := file.Profiles()
+			if profile != "" {
+				names = []string{profile}
+			}
+			if len(names) == 0 {
+				return fmt.Errorf("%s does not define any profiles", file.Path)
+			}
+
+			invalid := 0
+			for _, name := range names {
+				if err := validateProfile(file, name); err != nil {
+					invalid++
+					fmt.Fprintf(cmd.OutOrStdout(), "%s: %v\n", name, err)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: OK\n", name)
+			}
+			if invalid > 0 {
+				return fmt.Errorf("%d of %d profiles in %s are invalid", invalid, len(names), file.Path)
+			}
+			return nil
+		},
+	}
+)
+
+// loadConfigFile reads the configuration file selected by --config
+func loadConfigFile() (*config.File, error) {
+	path, err := config.ConfigFilePath(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadFile(path)
+}
+
+// validateProfile checks that the named profile has every required
+// setting, including secrets kept in the credential store, and that its
+// values can be used to build a Jira client
+func validateProfile(file *config.File, name string) error {
+	cfg, err := config.Load(config.Options{ConfigFile: file.Path, Profile: name, Passphrase: storePassphrase})
+	if err != nil {
+		return err
+	}
+	if _, err := url.ParseRequestURI(cfg.BaseURL); err != nil {
+		return fmt.Errorf("invalid base_url: %w", err)
+	}
+	if _, err := jira.ParseAPIVersion(cfg.APIVersion); err != nil {
+		return fmt.Errorf("invalid api_version: %w", err)
+	}
+	if _, err := jira.ParseCacheTTLs(cfg.CacheTTLs); err != nil {
+		return fmt.Errorf("invalid cache_ttls: %w", err)
+	}
+	if _, err := newLogger(cfg, io.Discard); err != nil {
+		return err
+	}
+	opts, err := transportOptions(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := opts.TLSConfig(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// runConfigWizard asks for the settings of a profile, checks that they
+// authenticate against Jira and saves them
+func runConfigWizard(cmd *cobra.Command, file *config.File, p *prompter) error {
+	out := cmd.OutOrStdout()
+
+	name, err := p.ask("Profile name", file.SelectProfile(profile))
+	if err != nil {
+		return err
+	}
+	current := func(key string) string {
+		if value, ok := file.Get(name, key); ok {
+			return fmt.Sprint(value)
+		}
+		return ""
+	}
+
+	baseURL, err := p.ask("Jira base URL", current("base_url"))
+	if err != nil {
+		return err
+	}
+	if u, err := url.ParseRequestURI(baseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid Jira base URL %q: expected e.g. https://jira.example.com", baseURL)
+	}
+
+	authType := current("auth_type")
+	if authType == "" {
+		authType = config.AuthBearer
+		if strings.HasSuffix(strings.TrimRight(baseURL, "/"), ".atlassian.net") {
+			authType = config.AuthBasic
+		}
+	}
+	authType, err = p.ask("Authentication type (bearer, basic, session or oauth)", authType)
+	if err != nil {
+		return err
+	}
+
+	// Secrets go to the encrypted credential store, not the config file
+	values := map[string]string{"base_url": baseURL, "auth_type": strings.ToLower(authType)}
+	secrets := map[string]string{}
+	switch values["auth_type"] {
+	case config.AuthBearer:
+		secrets["token"], err = p.askSecret("Personal access token")
+	case config.AuthBasic:
+		if values["username"], err = p.ask("Username or email", current("username")); err == nil {
+			secrets["token"], err = p.askSecret("API token")
+		}
+	case config.AuthSession:
+		secrets["session_cookie"], err = p.askSecret("Session cookie (e.g. JSESSIONID=...)")
+	case config.AuthOAuth:
+		if values["oauth_client_id"], err = p.ask("OAuth client ID", current("oauth_client_id")); err == nil {
+			secrets["oauth_client_secret"], err = p.askSecret("OAuth client secret")
+		}
+	default:
+		return fmt.Errorf("unsupported authentication type %q", authType)
+	}
+	if err != nil {
+		return err
+	}
+
+	apiVersion := current("api_version")
+	if apiVersion == "" {
+		apiVersion = config.DefaultAPIVersion
+	}
+	if values["api_version"], err = p.ask("REST API version (2, or 3 on Jira Cloud)", apiVersion); err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		if value == "" {
+			continue
+		}
+		if err := file.Set(name, key, value); err != nil {
+			return err
+		}
+	}
+	cfg, err := file.Config(name)
+	if err != nil {
+		return err
+	}
+	for key, value := range secrets {
+		if value == "" {
+			delete(secrets, key)
+		} else if err := config.SetSecret(&cfg, key, value); err != nil {
+			return err
+		}
+	}
+	if err := config.Validate(cfg); err != nil {
+		return err
+	}
+
+	switch {
+	case noValidate:
+	case cfg.AuthType == config.AuthOAuth:
+		fmt.Fprintln(out, "Run `owlify auth login` to finish setting up OAuth.")
+	default:
+		if err := checkCredentials(cmd, cfg); err != nil {
+			fmt.Fprintf(out, "Could not authenticate against Jira: %v\n", err)
+			save, err := p.confirm("Save the profile anyway?", false)
+			if err != nil {
+				return err
+			}
+			if !save {
+				return errors.New("profile not saved")
+			}
+		}
+	}
+
+	if len(secrets) > 0 {
+		store, err := openCredentialStore()
+		if err != nil {
+			return err
+		}
+		for key, value := range secrets {
+			if err := store.Set(name, key, value); err != nil {
+				return err
+			}
+			// Drop a plain text copy left by an earlier version of the profile
+			file.Unset(name, key)
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Stored the credentials of profile %s in %s\n", name, store.Path)
+	}
+
+	if !file.HasProfile(file.DefaultProfile()) {
+		file.SetDefaultProfile(name)
+	}
+	if err := file.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Saved profile %s to %s\n", name, file.Path)
+	return nil
+}
+
+// checkCredentials authenticates against Jira with cfg and reports who the
+// user is and what kind of instance was found
+func checkCredentials(cmd *cobra.Command, cfg config.JiraConfig) error {
+	cfg.CacheEnabled = false
+	client, err := newJiraClientFor(cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	user, err := client.Myself(cmd.Context())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Authenticated as %s\n", user.DisplayName)
+
+	if info, err := client.ServerInfo(cmd.Context()); err == nil && info.IsCloud() && client.APIVersion() != jira.APIVersion3 {
+		fmt.Fprintln(cmd.OutOrStdout(), "This is Jira Cloud; set api_version to 3 for Atlassian Document Format descriptions.")
+	}
+	return nil
+}
+
+// prompter asks questions on an input stream
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	// terminal is the file descriptor of an interactive input, or -1
+	terminal int
+}
+
+// newPrompter creates a prompter reading from in and writing to out
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	p := &prompter{in: bufio.NewReader(in), out: out, terminal: -1}
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		p.terminal = int(f.Fd())
+	}
+	return p
+}
+
+// ask returns the answer to a question, or def when it is left empty
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("error reading answer: %w", err)
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// askSecret asks for a value without echoing it on a terminal
+func (p *prompter) askSecret(question string) (string, error) {
+	if p.terminal < 0 {
+		return p.ask(question, "")
+	}
+	fmt.Fprintf(p.out, "%s: ", question)
+	secret, err := term.ReadPassword(p.terminal)
+	fmt.Fprintln(p.out)
+	if err != nil {
+		return "", fmt.Errorf("error reading answer: %w", err)
+	}
+	return strings.TrimSpace(string(secret)), nil
+}
+
+// confirm asks a yes/no question
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer, err := p.ask(question+" ("+hint+")", "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func init() {
+	configInitCmd.Flags().BoolVar(&noValidate, "no-validate", false, "Save the profile without checking the credentials against Jira")
+
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
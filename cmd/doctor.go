@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/morfo-si/owlify/pkg/config"
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+// diagnostic is one named value of a doctor or cache report
+type diagnostic struct {
+	Name  string
+	Value string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Show connection and TLS settings and test the connection to Jira",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.GetJiraConfig()
+		opts, err := transportOptions(cfg)
+		if err != nil {
+			return err
+		}
+
+		results := []diagnostic{
+			{"Profile", cfg.Profile},
+			{"Config file", valueOrNone(cfg.ConfigFile)},
+			{"Base URL", cfg.BaseURL},
+			{"Auth type", cfg.AuthType},
+			{"HTTP proxy", valueOrNone(cfg.HTTPProxy)},
+			{"HTTPS proxy", valueOrNone(cfg.HTTPSProxy)},
+			{"Request timeout", opts.Timeout.String()},
+			{"Minimum TLS version", tls.VersionName(opts.MinTLSVersion)},
+			{"CA bundle", valueOrNone(opts.CABundle)},
+		}
+
+		clientCert := "none"
+		if leaf, err := opts.ClientCertificateInfo(); err != nil {
+			clientCert = fmt.Sprintf("error: %v", err)
+		} else if leaf != nil {
+			clientCert = fmt.Sprintf("%s (%s, expires %s)", opts.ClientCert, leaf.Subject, leaf.NotAfter.Format(time.DateOnly))
+		}
+		results = append(results, diagnostic{"Client certificate", clientCert})
+
+		verify := "enabled"
+		if opts.InsecureSkipVerify {
+			verify = "DISABLED (insecure)"
+		}
+		results = append(results, diagnostic{"Certificate verification", verify})
+
+		results = append(results, checkConnection(cmd.Context())...)
+
+		if err := reports.GenerateReport(results, reports.OutputFormat(output)); err != nil {
+			return fmt.Errorf("error generating report: %w", err)
+		}
+		return nil
+	},
+}
+
+// checkConnection sends a test request to Jira and describes the outcome
+func checkConnection(ctx context.Context) []diagnostic {
+	client, err := newJiraClient(ctx)
+	if err != nil {
+		return []diagnostic{{"Connection", fmt.Sprintf("error: %v", err)}}
+	}
+	report, err := client.CheckConnection(ctx)
+	if err != nil {
+		return []diagnostic{{"Connection", fmt.Sprintf("error: %v", err)}}
+	}
+
+	results := []diagnostic{
+		{"Connection", fmt.Sprintf("%s %d in %s", report.Protocol, report.StatusCode, report.Duration.Round(time.Millisecond))},
+	}
+	if report.TLS != nil {
+		results = append(results,
+			diagnostic{"TLS version", report.TLS.Version},
+			diagnostic{"TLS cipher suite", report.TLS.CipherSuite},
+			diagnostic{"Server certificate", report.TLS.Subject},
+			diagnostic{"Server certificate issuer", report.TLS.Issuer},
+			diagnostic{"Server certificate expires", report.TLS.NotAfter.Format(time.DateOnly)},
+		)
+	}
+
+	if info, err := client.ServerInfo(ctx); err != nil {
+		results = append(results, diagnostic{"Deployment", fmt.Sprintf("unknown: %v", err)})
+	} else {
+		results = append(results,
+			diagnostic{"Deployment", info.DeploymentType},
+			diagnostic{"Jira version", info.Version},
+		)
+	}
+	return results
+}
+
+// valueOrNone returns value, or "none" when it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
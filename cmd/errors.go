@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/morfo-si/owlify/pkg/jira"
+)
+
+// Exit codes returned by owlify
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitUnauthorized = 3
+	ExitForbidden    = 4
+	ExitNotFound     = 5
+	ExitRateLimited  = 6
+	ExitServerError  = 7
+	ExitTimeout      = 8
+	ExitInterrupted  = 130 // Conventional exit code for SIGINT
+)
+
+// ExitCode maps an error returned by Execute to the process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case jira.IsUnauthorized(err):
+		return ExitUnauthorized
+	case jira.IsForbidden(err):
+		return ExitForbidden
+	case jira.IsNotFound(err):
+		return ExitNotFound
+	case jira.IsRateLimited(err):
+		return ExitRateLimited
+	case jira.IsServerError(err):
+		return ExitServerError
+	case errors.Is(err, context.DeadlineExceeded):
+		return ExitTimeout
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	default:
+		return ExitError
+	}
+}
+
+// errorHint returns an actionable suggestion for well-known Jira API errors
+func errorHint(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "The command did not finish in time; raise --timeout or --request-timeout."
+	}
+
+	var apiErr *jira.APIError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+
+	switch {
+	case jira.IsUnauthorized(err):
+		return "Jira rejected the credentials; check JIRA_AUTH_TYPE and that your token or session has not expired."
+	case jira.IsForbidden(err):
+		return "Your Jira account does not have permission for this operation."
+	case jira.IsNotFound(err):
+		return "Jira could not find the requested resource; check the key or ID and JIRA_BASE_URL."
+	case jira.IsRateLimited(err):
+		return "Jira is rate limiting requests; wait a moment and try again."
+	case jira.IsServerError(err):
+		return "Jira returned a server error; try again later or contact your Jira administrator."
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+var (
+	allFields bool
+
+	fieldCmd = &cobra.Command{
+		Use:   "field",
+		Short: "Inspect the JIRA fields owlify uses",
+	}
+
+	fieldListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "Show which field IDs story points, epic link, sprint and parent feature resolve to",
+		Long: `Show which field IDs story points, epic link, sprint and parent feature
+resolve to on this Jira instance.
+
+The IDs are discovered from the fields of the instance. When a field is not
+found, or the wrong one is, set its ID in the fields section of the profile,
+e.g. owlify config set fields.story_points customfield_10016.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			var data any
+			if allFields {
+				if data, err = client.Fields(cmd.Context()); err != nil {
+					return err
+				}
+			} else {
+				registry, err := client.FieldRegistry(cmd.Context())
+				if err != nil {
+					return err
+				}
+				data = registry.Mappings()
+			}
+			if err := reports.GenerateReport(data, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	fieldListCmd.Flags().BoolVar(&allFields, "all", false, "List every field of the instance instead")
+
+	fieldCmd.AddCommand(fieldListCmd)
+}
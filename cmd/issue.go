@@ -1,86 +1,510 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
 
+	"github.com/morfo-si/owlify/pkg/config"
 	"github.com/morfo-si/owlify/pkg/jira"
 	"github.com/morfo-si/owlify/pkg/reports"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	issueKey  string
-	newStatus string
+	issueKey        string
+	newStatus       string
+	descriptionFile string
+
+	// Flags of issue create
+	issueTemplate    string
+	issueType        string
+	issueSummary     string
+	issueDescription string
+	issueAssignee    string
+	issuePriority    string
+	issueLabels      []string
+	issueComponents  []string
+	issueEpic        string
+	issueParent      string
+	issueFields      []string
+	useEditor        bool
+
+	// Flags of issue update
+	unassign     bool
+	addLabels    []string
+	removeLabels []string
+	dueDate      string
+	storyPoints  float64
+
+	resolution        string
+	transitionComment string
 
 	issueCmd = &cobra.Command{
 		Use:   "issue",
 		Short: "Fetch a JIRA issue",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if issueKey == "" {
-				fmt.Println("Issue key is required")
-				return
+				return fmt.Errorf("issue key is required")
 			}
 
-			issue, err := jira.GetIssue(issueKey, jira.JIRAGetRequest)
+			client, err := newJiraClient(cmd.Context())
 			if err != nil {
-				fmt.Printf("Error fetching issue %s: %v\n", issueKey, err)
-				return
+				return err
+			}
+			issue, err := client.GetIssue(cmd.Context(), issueKey)
+			if err != nil {
+				return err
 			}
 
 			if err := reports.GenerateReport(issue, reports.OutputFormat(output)); err != nil {
-				fmt.Printf("Error generating report: %v\n", err)
-				return
+				return fmt.Errorf("error generating report: %w", err)
 			}
+			return nil
 		},
 	}
 
 	issueUpdateStatusCmd = &cobra.Command{
 		Use:   "update",
 		Short: "Update a JIRA issue fields",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `Update the fields of a JIRA issue and transition it to another status.
+
+The changes are checked against the fields on the edit screen of the issue
+before anything is sent. Labels are added and removed, so other labels are
+kept; components replace the components of the issue.
+
+Further fields are set with --field NAME=VALUE like on issue create.
+
+--status takes the name of a transition or of the status it leads to. Fields
+the transition requires, such as the resolution, are reported before the
+transition is made; set the resolution with --resolution and add a comment
+with --comment.`,
+		Example: `  owlify issue update -k PROJ-1 --summary "Export reports as CSV" --story-points 5
+  owlify issue update -k PROJ-1 --add-label reports --remove-label triage --due-date 2025-07-01
+  owlify issue update -k PROJ-1 --assignee jdoe --status "In Progress"
+  owlify issue update -k PROJ-1 --status Done --resolution Fixed --comment "Released in 1.2"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if issueKey == "" {
-				fmt.Println("Issue key is required")
-				return
+				return fmt.Errorf("issue key is required")
 			}
-			if newStatus == "" {
-				fmt.Println("Status is required")
-				return
+			update, err := issueUpdateFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			if newStatus == "" && (resolution != "" || transitionComment != "") {
+				return fmt.Errorf("--resolution and --comment are set on a transition and need --status")
+			}
+			if update.IsEmpty() && newStatus == "" {
+				return fmt.Errorf("nothing to update: set --status or a field to change")
 			}
 
-			// Fetch the issue to get the current status
-			issue, err := jira.GetIssue(issueKey, jira.JIRAGetRequest)
+			client, err := newJiraClient(cmd.Context())
 			if err != nil {
-				fmt.Printf("Error fetching issue %s: %v\n", issueKey, err)
-				return
+				return err
+			}
+
+			if !update.IsEmpty() {
+				if err := client.UpdateIssueFields(cmd.Context(), issueKey, update); err != nil {
+					return err
+				}
+				fmt.Printf("Successfully updated fields of issue %s\n", issueKey)
 			}
-			// Fetch the available status transitions
-			transitions, err := jira.GetAvailableTransitions(issue, jira.JIRAGetRequest)
+			if newStatus == "" {
+				return nil
+			}
+
+			// Fetch the available status transitions with their screens
+			transitions, err := client.GetAvailableTransitions(cmd.Context(), jira.Issue{Key: issueKey})
 			if err != nil {
-				fmt.Printf("Error fetching transitions for issue %s: %v\n", issueKey, err)
-				return
+				return err
 			}
 
 			// Validate transition name
-			status := jira.GetValidTransitionID(newStatus, transitions)
-			if status == "" {
-				fmt.Printf("Invalid transition name: %s\n", newStatus)
-				return
+			transition, ok := jira.FindTransition(newStatus, transitions)
+			if !ok {
+				return fmt.Errorf("invalid transition name: %s (see owlify issue transitions -k %s)", newStatus, issueKey)
 			}
 
 			// Update the issue status
-			err = jira.UpdateIssueStatus(issue.Key, status, jira.JIRAPostRequest)
+			options := jira.TransitionOptions{Resolution: resolution, Comment: transitionComment}
+			if err := client.TransitionIssue(cmd.Context(), issueKey, transition, options); err != nil {
+				return err
+			}
+			fmt.Printf("Successfully updated issue %s status to %s\n", issueKey, transition.To.Name)
+			return nil
+		},
+	}
+
+	issueTransitionsCmd = &cobra.Command{
+		Use:   "transitions",
+		Short: "List the transitions available for a JIRA issue",
+		Long: `List the transitions available for a JIRA issue, the status each leads to
+and the fields it requires.
+
+A required resolution is set with issue update --resolution.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			transitions, err := client.GetAvailableTransitions(cmd.Context(), jira.Issue{Key: issueKey})
+			if err != nil {
+				return err
+			}
+
+			rows := make([]transitionRow, len(transitions))
+			for i, t := range transitions {
+				rows[i] = transitionRow{ID: t.ID, Name: t.Name, To: t.To.Name, Requires: strings.Join(t.RequiredFields(), ", ")}
+			}
+			if err := reports.GenerateReport(rows, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+
+	issueDescriptionCmd = &cobra.Command{
+		Use:   "description",
+		Short: "Print or replace the description of a JIRA issue",
+		Long: `Print the description of a JIRA issue, or replace it with --set.
+
+On JIRA_API_VERSION=3 descriptions are converted between Markdown and
+Atlassian Document Format. On version 2 they are read and written as
+Jira wiki markup.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if descriptionFile == "" {
+				description, err := client.GetIssueDescription(cmd.Context(), issueKey)
+				if err != nil {
+					return err
+				}
+				fmt.Println(description)
+				return nil
+			}
+
+			description, err := readInput(descriptionFile)
+			if err != nil {
+				return err
+			}
+			if err := client.SetIssueDescription(cmd.Context(), issueKey, description); err != nil {
+				return err
+			}
+			fmt.Printf("Successfully updated description of issue %s\n", issueKey)
+			return nil
+		},
+	}
+
+	issueCreateCmd = &cobra.Command{
+		Use:   "create",
+		Short: "Create a JIRA issue",
+		Long: `Create a JIRA issue.
+
+The issue can be described by a YAML or JSON template given with --template,
+holding any of project, type, summary, description, assignee, priority,
+labels, components, epic, parent and fields. Flags override the template.
+
+The description is Markdown. It is read from --description, from a file or
+standard input with --description-file (- reads stdin), or written in
+$EDITOR with --editor.
+
+Further fields are set with --field NAME=VALUE, where NAME is a field ID,
+the field name shown in Jira or one of story_points, epic_link, sprint and
+parent_feature. Values are converted to what the field expects, e.g. an
+option or a user; a JSON object or array is sent as it is.`,
+		Example: `  owlify issue create -p PROJ -t Story --summary "Export reports as CSV" --field story_points=3
+  owlify issue create --template bug.yaml --summary "Crash on start" --description-file - < notes.md
+  owlify issue create -p PROJ --summary "Write release notes" --assignee jdoe --editor`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			issue, err := newIssueFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			created, err := client.CreateIssue(cmd.Context(), issue)
 			if err != nil {
-				fmt.Printf("Error updating issue %s status to %s: %v\n", issueKey, newStatus, err)
-				return
+				return err
 			}
-			fmt.Printf("Successfully updated issue %s status to %s\n", issueKey, newStatus)
+			fmt.Printf("Created issue %s: %s/browse/%s\n", created.Key, strings.TrimSuffix(config.GetJiraConfig().BaseURL, "/"), created.Key)
+			return nil
 		},
 	}
 )
 
+// transitionRow is one transition in the report of issue transitions
+type transitionRow struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	To       string `json:"to"`
+	Requires string `json:"requires"`
+}
+
+// issueTemplateFile is the YAML or JSON template of issue create
+type issueTemplateFile struct {
+	Project     string         `yaml:"project"`
+	Type        string         `yaml:"type"`
+	Summary     string         `yaml:"summary"`
+	Description string         `yaml:"description"`
+	Assignee    string         `yaml:"assignee"`
+	Priority    string         `yaml:"priority"`
+	Labels      []string       `yaml:"labels"`
+	Components  []string       `yaml:"components"`
+	Epic        string         `yaml:"epic"`
+	Parent      string         `yaml:"parent"`
+	Fields      map[string]any `yaml:"fields"`
+}
+
+// newIssueFromFlags builds the issue to create from the template and the
+// flags of issue create
+func newIssueFromFlags(cmd *cobra.Command) (jira.NewIssue, error) {
+	if issueTemplate == "-" && descriptionFile == "-" {
+		return jira.NewIssue{}, fmt.Errorf("--template and --description-file cannot both read stdin")
+	}
+
+	var tpl issueTemplateFile
+	if issueTemplate != "" {
+		data, err := readInput(issueTemplate)
+		if err != nil {
+			return jira.NewIssue{}, err
+		}
+		// JSON is valid YAML, so one decoder reads both
+		if err := yaml.Unmarshal([]byte(data), &tpl); err != nil {
+			return jira.NewIssue{}, fmt.Errorf("error reading issue template %s: %w", issueTemplate, err)
+		}
+	}
+
+	issue := jira.NewIssue{
+		Project:     tpl.Project,
+		Type:        tpl.Type,
+		Summary:     tpl.Summary,
+		Description: tpl.Description,
+		Assignee:    tpl.Assignee,
+		Priority:    tpl.Priority,
+		Labels:      tpl.Labels,
+		Components:  tpl.Components,
+		Epic:        tpl.Epic,
+		Parent:      tpl.Parent,
+		Fields:      tpl.Fields,
+	}
+	flags := cmd.Flags()
+	for name, target := range map[string]*string{
+		"project":  &issue.Project,
+		"type":     &issue.Type,
+		"summary":  &issue.Summary,
+		"assignee": &issue.Assignee,
+		"priority": &issue.Priority,
+		"epic":     &issue.Epic,
+		"parent":   &issue.Parent,
+	} {
+		// Profile defaults fill in flags, but must not override the template
+		if flag := flags.Lookup(name); flag.Changed || (*target == "" && flag.Value.String() != "") {
+			*target = flag.Value.String()
+		}
+	}
+	if flags.Changed("label") {
+		issue.Labels = issueLabels
+	}
+	if flags.Changed("component") {
+		issue.Components = issueComponents
+	}
+	if issue.Type == "" {
+		issue.Type = "Task"
+	}
+
+	fields, err := fieldFlags()
+	if err != nil {
+		return jira.NewIssue{}, err
+	}
+	for name, value := range fields {
+		if issue.Fields == nil {
+			issue.Fields = map[string]any{}
+		}
+		issue.Fields[name] = value
+	}
+
+	switch {
+	case flags.Changed("description"):
+		issue.Description = issueDescription
+	case descriptionFile != "":
+		description, err := readInput(descriptionFile)
+		if err != nil {
+			return jira.NewIssue{}, err
+		}
+		issue.Description = description
+	}
+	if useEditor {
+		description, err := editText(issue.Description)
+		if err != nil {
+			return jira.NewIssue{}, err
+		}
+		issue.Description = description
+	}
+	issue.Description = strings.TrimSpace(issue.Description)
+	return issue, nil
+}
+
+// issueUpdateFromFlags builds the field changes of issue update from its flags
+func issueUpdateFromFlags(cmd *cobra.Command) (jira.IssueUpdate, error) {
+	update := jira.IssueUpdate{
+		Summary:      issueSummary,
+		Assignee:     issueAssignee,
+		Unassign:     unassign,
+		Priority:     issuePriority,
+		AddLabels:    addLabels,
+		RemoveLabels: removeLabels,
+		Components:   issueComponents,
+		DueDate:      dueDate,
+	}
+	if cmd.Flags().Changed("story-points") {
+		update.StoryPoints = &storyPoints
+	}
+	fields, err := fieldFlags()
+	if err != nil {
+		return jira.IssueUpdate{}, err
+	}
+	update.Fields = fields
+	return update, nil
+}
+
+// fieldFlags returns the fields given with --field NAME=VALUE
+func fieldFlags() (map[string]any, error) {
+	var fields map[string]any
+	for _, field := range issueFields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --field %q: expected NAME=VALUE", field)
+		}
+		if fields == nil {
+			fields = map[string]any{}
+		}
+		fields[name] = fieldFlagValue(value)
+	}
+	return fields, nil
+}
+
+// fieldFlagValue returns the value of a --field flag. JSON objects and
+// arrays are decoded so they are sent as they are; anything else is a string.
+func fieldFlagValue(value string) any {
+	if trimmed := strings.TrimSpace(value); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var decoded any
+		if err := json.Unmarshal([]byte(trimmed), &decoded); err == nil {
+			return decoded
+		}
+	}
+	return value
+}
+
+// editText opens text in the user's editor, taken from $VISUAL or $EDITOR,
+// and returns the edited text
+func editText(text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", "owlify-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	// The editor may come with arguments, such as "code --wait"
+	args := append(strings.Fields(editor), file.Name())
+	editorCmd := exec.Command(args[0], args[1:]...)
+	editorCmd.Stdin, editorCmd.Stdout, editorCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("error running editor %s: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// readInput reads the contents of path, or of stdin when path is "-"
+func readInput(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
 func init() {
 	issueCmd.PersistentFlags().StringVarP(&issueKey, "key", "k", "", "JIRA issue key (required)")
-	issueUpdateStatusCmd.PersistentFlags().StringVarP(&newStatus, "status", "s", "", "New status (required)")
+	issueUpdateStatusCmd.PersistentFlags().StringVarP(&newStatus, "status", "s", "", "Transition the issue to this status")
+	issueUpdateStatusCmd.Flags().StringVar(&issueSummary, "summary", "", "New summary")
+	issueUpdateStatusCmd.Flags().StringVar(&issueAssignee, "assignee", "", "Username, account ID, email address or name of the new assignee")
+	issueUpdateStatusCmd.Flags().BoolVar(&unassign, "unassign", false, "Remove the assignee")
+	issueUpdateStatusCmd.Flags().StringVar(&issuePriority, "priority", "", "New priority name")
+	issueUpdateStatusCmd.Flags().StringSliceVar(&addLabels, "add-label", nil, "Label to add, repeat or separate with commas for several")
+	issueUpdateStatusCmd.Flags().StringSliceVar(&removeLabels, "remove-label", nil, "Label to remove, repeat or separate with commas for several")
+	issueUpdateStatusCmd.Flags().StringSliceVar(&issueComponents, "component", nil, "Component name replacing the current components, repeat or separate with commas for several")
+	issueUpdateStatusCmd.Flags().StringVar(&dueDate, "due-date", "", "New due date as YYYY-MM-DD")
+	issueUpdateStatusCmd.Flags().Float64Var(&storyPoints, "story-points", 0, "New story points")
+	issueUpdateStatusCmd.Flags().StringArrayVar(&issueFields, "field", nil, "Further field as NAME=VALUE, repeat for several")
+	issueUpdateStatusCmd.Flags().StringVar(&resolution, "resolution", "", "Resolution to set with the transition, e.g. Fixed")
+	issueUpdateStatusCmd.Flags().StringVar(&transitionComment, "comment", "", "Comment in Markdown to add with the transition")
+	issueUpdateStatusCmd.MarkFlagsMutuallyExclusive("assignee", "unassign")
+
+	issueDescriptionCmd.Flags().StringVar(&descriptionFile, "set", "", "Replace the description with the Markdown in this file (- reads stdin)")
+
+	issueCreateCmd.Flags().StringVar(&issueTemplate, "template", "", "YAML or JSON file describing the issue (- reads stdin)")
+	issueCreateCmd.Flags().StringVarP(&project, "project", "p", "", "JIRA project key")
+	issueCreateCmd.Flags().StringVarP(&issueType, "type", "t", "", "Issue type (default Task)")
+	issueCreateCmd.Flags().StringVar(&issueSummary, "summary", "", "Summary")
+	issueCreateCmd.Flags().StringVar(&issueDescription, "description", "", "Description in Markdown")
+	issueCreateCmd.Flags().StringVar(&descriptionFile, "description-file", "", "Read the description from this file (- reads stdin)")
+	issueCreateCmd.Flags().BoolVar(&useEditor, "editor", false, "Write the description in $EDITOR")
+	issueCreateCmd.Flags().StringVar(&issueAssignee, "assignee", "", "Username, account ID, email address or name of the assignee")
+	issueCreateCmd.Flags().StringVar(&issuePriority, "priority", "", "Priority name")
+	issueCreateCmd.Flags().StringSliceVar(&issueLabels, "label", nil, "Label, repeat or separate with commas for several")
+	issueCreateCmd.Flags().StringSliceVar(&issueComponents, "component", nil, "Component name, repeat or separate with commas for several")
+	issueCreateCmd.Flags().StringVar(&issueEpic, "epic", "", "Key of the epic the issue belongs to")
+	issueCreateCmd.Flags().StringVar(&issueParent, "parent", "", "Key of the parent issue, e.g. for a sub-task")
+	issueCreateCmd.Flags().StringArrayVar(&issueFields, "field", nil, "Further field as NAME=VALUE, repeat for several")
+	issueCreateCmd.MarkFlagsMutuallyExclusive("description", "description-file", "editor")
 
 	issueCmd.AddCommand(issueUpdateStatusCmd)
+	issueCmd.AddCommand(issueDescriptionCmd)
+	issueCmd.AddCommand(issueCreateCmd)
+	issueCmd.AddCommand(issueTransitionsCmd)
 }
@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/morfo-si/owlify/pkg/jira"
 	"github.com/morfo-si/owlify/pkg/reports"
 	"github.com/spf13/cobra"
 )
@@ -17,12 +16,16 @@ var (
 			if jql == "" {
 				return fmt.Errorf("jql is required")
 			}
-			issues, err := jira.FetchIssuesFromJQL(jql, jira.JIRAGetRequest)
+			client, err := newJiraClient(cmd.Context())
 			if err != nil {
-				return fmt.Errorf("error fetching JIRA issues: %v", err)
+				return err
+			}
+			issues, err := client.FetchIssuesFromJQL(cmd.Context(), jql, limit)
+			if err != nil {
+				return fmt.Errorf("error fetching JIRA issues: %w", err)
 			}
 			if err := reports.GenerateReport(issues, reports.OutputFormat(output)); err != nil {
-				return fmt.Errorf("error generating report: %v", err)
+				return fmt.Errorf("error generating report: %w", err)
 			}
 			return nil
 		},
@@ -31,6 +34,7 @@ var (
 
 func init() {
 	jqlCmd.Flags().StringVarP(&jql, "jql", "j", "", "JQL query (required)")
+	jqlCmd.Flags().IntVarP(&limit, "limit", "l", 0, "Maximum number of issues to fetch (0 for all)")
 	if err := jqlCmd.MarkFlagRequired("jql"); err != nil {
 		fmt.Printf("Error marking jql flag as required: %v\n", err)
 	}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/morfo-si/owlify/pkg/config"
+)
+
+// newLogger creates the logger for diagnostics written to w. The --debug
+// and --log-format flags take precedence over OWLIFY_LOG_LEVEL and
+// OWLIFY_LOG_FORMAT.
+func newLogger(cfg config.JiraConfig, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return nil, fmt.Errorf("invalid OWLIFY_LOG_LEVEL %q: must be debug, info, warn or error", cfg.LogLevel)
+	}
+	if debug {
+		level = slog.LevelDebug
+	}
+
+	format := cfg.LogFormat
+	if logFormat != "" {
+		format = logFormat
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case "text", "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+}
@@ -1,15 +1,55 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/morfo-si/owlify/pkg/config"
+	"github.com/morfo-si/owlify/pkg/jira"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	output    string
+	output      string
 	showVersion bool
+	limit       int
+
+	retryMaxAttempts int
+	retryMaxWait     time.Duration
+	requestTimeout   time.Duration
+	timeout          time.Duration
+
+	insecureSkipVerify bool
+
+	noCache      bool
+	refreshCache bool
+
+	// Cassette directories for recording or replaying Jira traffic
+	recordDir string
+	replayDir string
+
+	debug     bool
+	logFormat string
+
+	// Configuration file and profile selected with --config and --profile
+	configFile string
+	profile    string
+
+	// httpClient is shared by every Jira client so they reuse one connection pool
+	httpClient     *http.Client
+	httpClientErr  error
+	httpClientOnce sync.Once
+
+	// cancelTimeout releases the deadline installed for --timeout
+	cancelTimeout context.CancelFunc = func() {}
 
 	// Version information
 	versionInfo struct {
@@ -21,6 +61,22 @@ var (
 	rootCmd = &cobra.Command{
 		Use:   "owlify",
 		Short: "A CLI tool to fetch JIRA issues",
+		// Errors from Jira are not usage errors; keep the hint visible
+		SilenceUsage: true,
+		// Execute prints the error followed by its hint
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadConfig(cmd); err != nil {
+				return err
+			}
+
+			if timeout > 0 {
+				var ctx context.Context
+				ctx, cancelTimeout = context.WithTimeout(cmd.Context(), timeout)
+				cmd.SetContext(ctx)
+			}
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if showVersion {
 				fmt.Printf("Owlify version %s\n", versionInfo.Version)
@@ -33,6 +89,92 @@ var (
 	}
 )
 
+// Commands are annotated with configAnnotation to tell how much of the
+// configuration they need. Commands without it talk to Jira and need
+// configRequired.
+const (
+	configAnnotation = "owlify/config"
+	// configNone commands do not read the configuration at all
+	configNone = "none"
+	// configOptional commands read the configuration but work without credentials
+	configOptional = "optional"
+	// configRequired commands need the Jira URL and credentials
+	configRequired = "required"
+)
+
+// configNeeds returns how much of the configuration cmd needs, inherited
+// from its parents. The root command itself only prints help or the
+// version, as do cobra's help and completion commands.
+func configNeeds(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return configNone
+	}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if needs, ok := c.Annotations[configAnnotation]; ok {
+			return needs
+		}
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return configNone
+		}
+	}
+	return configRequired
+}
+
+// loadConfig loads the configuration for cmd, checking that the settings
+// needed to talk to Jira are present only for commands that do
+func loadConfig(cmd *cobra.Command) error {
+	needs := configNeeds(cmd)
+	if needs == configNone {
+		return nil
+	}
+
+	// Replayed traffic needs no credentials, so a cassette can be replayed
+	// without any configuration
+	if needs == configRequired && replayDir != "" {
+		needs = configOptional
+	}
+
+	opts := config.Options{ConfigFile: configFile, Profile: profile}
+	// Only commands talking to Jira unlock the credential store
+	if needs == configRequired {
+		opts.Passphrase = storePassphrase
+	}
+	if err := config.Initialize(opts); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	cfg := config.GetJiraConfig()
+	if needs == configRequired {
+		if err := config.Validate(cfg); err != nil {
+			return fmt.Errorf("configuration error: %w", err)
+		}
+	}
+	return applyProfileDefaults(cmd, cfg)
+}
+
+// applyProfileDefaults sets the flags of cmd that were not given on the
+// command line to the defaults of the configuration profile
+func applyProfileDefaults(cmd *cobra.Command, cfg config.JiraConfig) error {
+	defaults := map[string]string{
+		"output":  cfg.DefaultOutput,
+		"project": cfg.DefaultProject,
+	}
+	if cfg.DefaultBoard > 0 {
+		defaults["board"] = strconv.Itoa(cfg.DefaultBoard)
+	}
+
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || value == "" {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid default %s %q in profile %q: %w", name, value, cfg.Profile, err)
+		}
+	}
+	return nil
+}
+
 // SetVersionInfo sets the version information
 func SetVersionInfo(version, commit, date string) {
 	versionInfo.Version = version
@@ -41,7 +183,21 @@ func SetVersionInfo(version, commit, date string) {
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Configuration file (default from OWLIFY_CONFIG or config.yaml in the owlify config directory)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Configuration profile to use (default from OWLIFY_PROFILE or default_profile)")
 	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "table", "Output format: table or json or csv")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Deadline for the whole command, e.g. 2m (default no deadline)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 0, "Timeout for a single Jira request (default from JIRA_REQUEST_TIMEOUT or 30s)")
+	rootCmd.PersistentFlags().IntVar(&retryMaxAttempts, "retry-max-attempts", 0, "Maximum attempts per Jira request, 1 disables retries (default from JIRA_RETRY_MAX_ATTEMPTS or 4)")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxWait, "retry-max-wait", 0, "Maximum wait between Jira request attempts (default from JIRA_RETRY_MAX_WAIT or 30s)")
+	rootCmd.PersistentFlags().BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Skip TLS certificate verification (INSECURE, lab instances only)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the response cache")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Ignore cached responses and fetch fresh data from Jira")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Record Jira traffic with credentials redacted into a cassette directory")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Replay Jira traffic from a cassette directory instead of the network")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Log every Jira request to stderr (same as OWLIFY_LOG_LEVEL=debug)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "Log format: text or json (default from OWLIFY_LOG_FORMAT or text)")
 	rootCmd.Flags().BoolVarP(&showVersion, "version", "v", false, "Show version information")
 
 	// Add commands to root command
@@ -49,13 +205,235 @@ func init() {
 	rootCmd.AddCommand(boardCmd)
 	rootCmd.AddCommand(jqlCmd)
 	rootCmd.AddCommand(issueCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(userCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(fieldCmd)
+	rootCmd.AddCommand(timesheetCmd)
 
 	if err := viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output")); err != nil {
 		fmt.Printf("Error binding output flag: %v\n", err)
 	}
 }
 
-// Execute executes the root command
+// replayBaseURL is the base URL of replays when none is configured
+const replayBaseURL = "https://jira.invalid"
+
+// newJiraClient creates a Jira client from the loaded configuration
+func newJiraClient(ctx context.Context) (*jira.Client, error) {
+	return newJiraClientFor(ctx, config.GetJiraConfig())
+}
+
+// newJiraClientFor creates a Jira client for the given configuration
+func newJiraClientFor(ctx context.Context, cfg config.JiraConfig) (*jira.Client, error) {
+	baseURL := cfg.BaseURL
+	apiVersion, err := jira.ParseAPIVersion(cfg.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JIRA_API_VERSION: %w", err)
+	}
+
+	// Replayed traffic is answered locally, so no credentials are involved,
+	// and replays match requests regardless of the host
+	var auth jira.Authenticator
+	if replayDir == "" {
+		if auth, err = newAuthenticator(cfg); err != nil {
+			return nil, err
+		}
+	} else if baseURL == "" {
+		baseURL = replayBaseURL
+	}
+	// OAuth requests go through the Atlassian API gateway instead of the site URL
+	if oauth, ok := auth.(*jira.OAuthAuth); ok {
+		token, err := oauth.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		baseURL = token.APIBaseURL()
+	}
+
+	httpClient, err := sharedHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	logger, err := newLogger(cfg, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []jira.ClientOption{
+		jira.WithHTTPClient(httpClient),
+		jira.WithLogger(logger),
+		jira.WithAPIVersion(apiVersion),
+		jira.WithFieldIDs(cfg.Fields),
+		jira.WithRetryPolicy(retryPolicy(cfg)),
+		jira.WithRateLimiter(jira.NewRateLimiter(cfg.RateLimit, cfg.RateBurst)),
+	}
+	// Cached responses would be missing from a recording or hide a replay
+	if cfg.CacheEnabled && !noCache && recordDir == "" && replayDir == "" {
+		cache, err := responseCache(cfg)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, jira.WithCache(cache))
+	}
+
+	return jira.NewClient(baseURL, auth, options...), nil
+}
+
+// responseCache returns the on-disk response cache configured for this run
+func responseCache(cfg config.JiraConfig) (*jira.Cache, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	ttls, err := jira.ParseCacheTTLs(cfg.CacheTTLs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JIRA_CACHE_TTLS: %w", err)
+	}
+	return jira.NewCache(dir, cfg.Profile,
+		jira.WithCacheTTLs(ttls),
+		jira.WithCacheRefresh(refreshCache)), nil
+}
+
+// sharedHTTPClient returns the HTTP client used for every Jira request of
+// this process, creating it from the flags and configuration on first use
+func sharedHTTPClient(cfg config.JiraConfig) (*http.Client, error) {
+	httpClientOnce.Do(func() {
+		opts, err := transportOptions(cfg)
+		if err != nil {
+			httpClientErr = err
+			return
+		}
+		if opts.InsecureSkipVerify {
+			fmt.Fprintln(os.Stderr, "WARNING: TLS certificate verification is disabled; only use this against lab instances")
+		}
+		httpClient, httpClientErr = jira.NewHTTPClient(opts)
+		if httpClientErr == nil {
+			httpClientErr = wrapTransport(httpClient)
+		}
+	})
+	return httpClient, httpClientErr
+}
+
+// wrapTransport routes the client through a cassette recorder or replayer
+// when --record or --replay is given
+func wrapTransport(client *http.Client) error {
+	switch {
+	case recordDir != "":
+		recorder, err := jira.NewRecorder(recordDir, client.Transport)
+		if err != nil {
+			return err
+		}
+		client.Transport = recorder
+	case replayDir != "":
+		replayer, err := jira.NewReplayer(replayDir)
+		if err != nil {
+			return err
+		}
+		client.Transport = replayer
+	}
+	return nil
+}
+
+// transportOptions builds the transport options from the flags, falling back
+// to the configuration and then to the package defaults
+func transportOptions(cfg config.JiraConfig) (jira.TransportOptions, error) {
+	opts := jira.DefaultTransportOptions()
+	if cfg.RequestTimeout > 0 {
+		opts.Timeout = cfg.RequestTimeout
+	}
+	if requestTimeout > 0 {
+		opts.Timeout = requestTimeout
+	}
+
+	opts.CABundle = cfg.CABundle
+	opts.ClientCert = cfg.ClientCert
+	opts.ClientKey = cfg.ClientKey
+	opts.InsecureSkipVerify = cfg.InsecureSkipVerify || insecureSkipVerify
+	opts.HTTPProxy = cfg.HTTPProxy
+	opts.HTTPSProxy = cfg.HTTPSProxy
+	if cfg.TLSMinVersion != "" {
+		version, err := jira.ParseTLSVersion(cfg.TLSMinVersion)
+		if err != nil {
+			return opts, fmt.Errorf("invalid JIRA_TLS_MIN_VERSION: %w", err)
+		}
+		opts.MinTLSVersion = version
+	}
+	return opts, nil
+}
+
+// newAuthenticator returns the authentication strategy selected by the configuration
+func newAuthenticator(cfg config.JiraConfig) (jira.Authenticator, error) {
+	switch cfg.AuthType {
+	case config.AuthBasic:
+		return jira.BasicAuth{Username: cfg.Username, Token: cfg.Token}, nil
+	case config.AuthSession:
+		return jira.SessionCookieAuth{Cookie: cfg.SessionCookie}, nil
+	case config.AuthOAuth:
+		tokenPath, err := config.OAuthTokenPath()
+		if err != nil {
+			return nil, err
+		}
+		oauth, err := oauthConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return jira.NewOAuthAuth(oauth, jira.FileTokenStore{Path: tokenPath}), nil
+	default:
+		return jira.BearerAuth{Token: cfg.Token}, nil
+	}
+}
+
+// oauthConfig returns the OAuth application settings from the configuration
+func oauthConfig(cfg config.JiraConfig) (jira.OAuthConfig, error) {
+	httpClient, err := sharedHTTPClient(cfg)
+	if err != nil {
+		return jira.OAuthConfig{}, err
+	}
+	return jira.OAuthConfig{
+		ClientID:     cfg.OAuthClientID,
+		ClientSecret: cfg.OAuthClientSecret,
+		RedirectURL:  cfg.OAuthRedirectURL,
+		Scopes:       cfg.OAuthScopes,
+		HTTPClient:   httpClient,
+	}, nil
+}
+
+// retryPolicy builds the retry policy from the flags, falling back to the
+// configuration and then to the package defaults
+func retryPolicy(cfg config.JiraConfig) jira.RetryPolicy {
+	policy := jira.DefaultRetryPolicy()
+	if cfg.RetryMaxAttempts > 0 {
+		policy.MaxAttempts = cfg.RetryMaxAttempts
+	}
+	if cfg.RetryMaxWait > 0 {
+		policy.MaxWait = cfg.RetryMaxWait
+	}
+	if retryMaxAttempts > 0 {
+		policy.MaxAttempts = retryMaxAttempts
+	}
+	if retryMaxWait > 0 {
+		policy.MaxWait = retryMaxWait
+	}
+	return policy
+}
+
+// Execute executes the root command and prints its error, if any, with a
+// hint on how to fix it. Interrupting the process with Ctrl-C or SIGTERM
+// cancels the command's context, aborting in-flight requests.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func() { cancelTimeout() }()
+
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if hint := errorHint(err); hint != "" {
+			fmt.Fprintln(os.Stderr, hint)
+		}
+	}
+	return err
 }
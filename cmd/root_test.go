@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/morfo-si/owlify/pkg/config"
+	"github.com/morfo-si/owlify/pkg/jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayWithEmptyConfig(t *testing.T) {
+	// Record a search against a fake Jira
+	dir := filepath.Join(t.TempDir(), "cassette")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/serverInfo":
+			_ = json.NewEncoder(w).Encode(jira.ServerInfo{DeploymentType: jira.DeploymentServer})
+		case "/rest/api/2/field":
+			_ = json.NewEncoder(w).Encode([]jira.Field{})
+		default:
+			_ = json.NewEncoder(w).Encode(jira.JiraResponse{Issues: []jira.Issue{{Key: "TEST-1"}}})
+		}
+	}))
+	recorder, err := jira.NewRecorder(dir, http.DefaultTransport)
+	require.NoError(t, err)
+	recording := jira.NewClient(server.URL, jira.BearerAuth{Token: "token"}, jira.WithHTTPClient(&http.Client{Transport: recorder}))
+	_, err = recording.FetchIssuesFromJQL(context.Background(), "project = TEST", 0)
+	require.NoError(t, err)
+	server.Close()
+
+	// Replay it without base URL, credentials or config file
+	t.Setenv("JIRA_BASE_URL", "")
+	t.Setenv("JIRA_TOKEN", "")
+	file, err := config.LoadFile("")
+	require.NoError(t, err)
+	cfg, err := file.Config("")
+	require.NoError(t, err)
+	require.Empty(t, cfg.BaseURL)
+
+	replayDir = dir
+	t.Cleanup(func() { replayDir = "" })
+	client, err := newJiraClientFor(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, replayBaseURL, client.BaseURL())
+
+	issues, err := client.FetchIssuesFromJQL(context.Background(), "project = TEST", 0)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "TEST-1", issues[0].Key)
+}
@@ -23,13 +23,17 @@ var (
 				return fmt.Errorf("sprint id is required")
 			}
 
-			issues, err := jira.FetchSprintIssues(sprintId, jira.JIRAGetRequest, features)
+			client, err := newJiraClient(cmd.Context())
 			if err != nil {
-				return fmt.Errorf("error fetching JIRA issues: %v", err)
+				return err
+			}
+			issues, err := client.FetchSprintIssues(cmd.Context(), sprintId, features)
+			if err != nil {
+				return fmt.Errorf("error fetching JIRA issues: %w", err)
 			}
 			if len(issues) > 0 {
 				if err := reports.GenerateReport(issues, reports.OutputFormat(output)); err != nil {
-					return fmt.Errorf("error generating report: %v", err)
+					return fmt.Errorf("error generating report: %w", err)
 				}
 			} else {
 				fmt.Println("No issues found for the specified criteria.")
@@ -57,16 +61,21 @@ var (
 				return fmt.Errorf("invalid sprint state: %s", state)
 			}
 
-			sprints, err := jira.FetchSprints(
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			sprints, err := client.FetchSprints(
+				cmd.Context(),
 				boardId,
-				jira.JIRAGetRequest,
-				jira.WithSprintState(jira.SprintState(state)))
+				jira.WithSprintState(jira.SprintState(state)),
+				jira.WithLimit(limit))
 			if err != nil {
-				return fmt.Errorf("error fetching sprints: %v", err)
+				return fmt.Errorf("error fetching sprints: %w", err)
 			}
 
 			if err := reports.GenerateReport(sprints, reports.OutputFormat(output)); err != nil {
-				return fmt.Errorf("error generating report: %v", err)
+				return fmt.Errorf("error generating report: %w", err)
 			}
 			return nil
 		},
@@ -80,15 +89,19 @@ var (
 				return fmt.Errorf("sprint id is required")
 			}
 
-			sprint, err := jira.FetchSprintByID(sprintId, jira.JIRAGetRequest)
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			sprint, err := client.FetchSprintByID(cmd.Context(), sprintId)
 			if err != nil {
-				return fmt.Errorf("error fetching sprint: %v", err)
+				return fmt.Errorf("error fetching sprint: %w", err)
 			}
 
 			// Wrap the single Sprint in a slice for the report generator
 			sprintSlice := []jira.Sprint{sprint}
 			if err := reports.GenerateReport(sprintSlice, reports.OutputFormat(output)); err != nil {
-				return fmt.Errorf("error generating report: %v", err)
+				return fmt.Errorf("error generating report: %w", err)
 			}
 			return nil
 		},
@@ -101,6 +114,7 @@ func init() {
 	sprintCmd.Flags().IntVarP(&sprintId, "id", "i", 0, "JIRA sprint ID (required)")
 	sprintListCmd.Flags().IntVarP(&boardId, "board", "b", 0, "JIRA board ID (required)")
 	sprintListCmd.Flags().StringVarP(&state, "state", "s", "active", "Sprint state (a/active, c/closed, f/future)")
+	sprintListCmd.Flags().IntVarP(&limit, "limit", "l", 0, "Maximum number of sprints to fetch (0 for all)")
 	sprintGetCmd.Flags().IntVarP(&sprintId, "id", "i", 0, "JIRA sprint ID (required)")
 
 	// Add the fetch-features flag
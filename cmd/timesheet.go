@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/morfo-si/owlify/pkg/jira"
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timesheetFrom string
+	timesheetTo   string
+
+	timesheetCmd = &cobra.Command{
+		Use:   "timesheet",
+		Short: "Sum the work logged per user, day and issue",
+		Long: `Sum the work logged per user, day and issue between two days, both
+included, on the issues matching a JQL query.
+
+The range defaults to the current week up to today. Every worklog of the
+matching issues is counted, whoever logged it; restrict the issues with
+worklogAuthor in the query to see the time of some users only.`,
+		Example: `  owlify timesheet -j "project = PROJ"
+  owlify timesheet -j "worklogAuthor = currentUser()" --from 2025-06-01 --to 2025-06-30 -o csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jql == "" {
+				return fmt.Errorf("jql is required")
+			}
+			from, to, err := timesheetRange(time.Now())
+			if err != nil {
+				return err
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			entries, err := client.Timesheet(cmd.Context(), jql, from, to)
+			if err != nil {
+				return err
+			}
+
+			rows := make([]timesheetRow, len(entries))
+			for i, entry := range entries {
+				rows[i] = timesheetRow{
+					User:    entry.Author.DisplayName,
+					Date:    entry.Day.Format(time.DateOnly),
+					Issue:   entry.IssueKey,
+					Summary: entry.Summary,
+					Time:    jira.FormatDuration(entry.TimeSpent),
+					Hours:   math.Round(entry.TimeSpent.Hours()*100) / 100,
+				}
+			}
+			if err := reports.GenerateReport(rows, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+)
+
+// timesheetRow is the time a user logged on an issue in a day
+type timesheetRow struct {
+	User    string  `json:"user"`
+	Date    string  `json:"date"`
+	Issue   string  `json:"issue"`
+	Summary string  `json:"summary"`
+	Time    string  `json:"time"`
+	Hours   float64 `json:"hours"`
+}
+
+// timesheetRange returns the days of --from and --to, by default the
+// Monday of the week of now and now
+func timesheetRange(now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	from := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	to := today
+
+	var err error
+	if timesheetFrom != "" {
+		if from, err = time.ParseInLocation(time.DateOnly, timesheetFrom, time.Local); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from %q: expected a date such as 2006-01-02", timesheetFrom)
+		}
+	}
+	if timesheetTo != "" {
+		if to, err = time.ParseInLocation(time.DateOnly, timesheetTo, time.Local); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to %q: expected a date such as 2006-01-02", timesheetTo)
+		}
+	}
+	return from, to, nil
+}
+
+func init() {
+	timesheetCmd.Flags().StringVarP(&jql, "jql", "j", "", "JQL query selecting the issues (required)")
+	timesheetCmd.Flags().StringVar(&timesheetFrom, "from", "", "First day, such as 2025-06-01 (default Monday of this week)")
+	timesheetCmd.Flags().StringVar(&timesheetTo, "to", "", "Last day, such as 2025-06-30 (default today)")
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+var (
+	userCmd = &cobra.Command{
+		Use:   "user",
+		Short: "Look up JIRA users",
+	}
+
+	userSearchCmd = &cobra.Command{
+		Use:   "search QUERY",
+		Short: "Find JIRA users by name, display name or email address",
+		Long: `Find JIRA users by name, display name or email address.
+
+Jira Cloud only returns account IDs and display names, while Server and
+Data Center also return usernames.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			users, err := client.FindUsers(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if len(users) == 0 {
+				return fmt.Errorf("no users found matching %q", args[0])
+			}
+			if err := reports.GenerateReport(users, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+)
+
+func init() {
+	userCmd.AddCommand(userSearchCmd)
+}
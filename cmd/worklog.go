@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morfo-si/owlify/pkg/jira"
+	"github.com/morfo-si/owlify/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+var (
+	worklogTime    string
+	worklogStarted string
+	worklogComment string
+	worklogLimit   int
+
+	issueWorklogCmd = &cobra.Command{
+		Use:   "worklog",
+		Short: "Log work on a JIRA issue and list its worklogs",
+	}
+
+	issueWorklogAddCmd = &cobra.Command{
+		Use:   "add",
+		Short: "Log time spent on a JIRA issue",
+		Long: `Log time spent on a JIRA issue.
+
+The time is given in Jira's syntax, such as "1w 2d 3h 4m" or "1h30m", where
+a week is 5 days and a day 8 hours. The work started now unless --started
+gives a date and time ("2006-01-02 15:04"), a date or a time of today.`,
+		Example: `  owlify issue worklog add -k PROJ-1 --time 1h30m
+  owlify issue worklog add -k PROJ-1 --time 2h --started "2025-06-02 09:00" --comment "Code review"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+			spent, err := jira.ParseDuration(worklogTime)
+			if err != nil {
+				return err
+			}
+			var started time.Time
+			if worklogStarted != "" {
+				if started, err = parseStarted(worklogStarted, time.Now()); err != nil {
+					return err
+				}
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			worklog, err := client.AddWorklog(cmd.Context(), issueKey, jira.NewWorklog{
+				TimeSpent:  spent,
+				Started:    started,
+				Comment:    worklogComment,
+				Visibility: visibilityFromFlags(),
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Logged %s on issue %s (worklog %s)\n", jira.FormatDuration(spent), issueKey, worklog.ID)
+			return nil
+		},
+	}
+
+	issueWorklogListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the worklogs of a JIRA issue, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if issueKey == "" {
+				return fmt.Errorf("issue key is required")
+			}
+
+			client, err := newJiraClient(cmd.Context())
+			if err != nil {
+				return err
+			}
+			worklogs, err := client.FetchWorklogs(cmd.Context(), issueKey, worklogLimit)
+			if err != nil {
+				return err
+			}
+
+			rows := make([]worklogRow, len(worklogs))
+			for i, worklog := range worklogs {
+				rows[i] = worklogRow{
+					ID:         worklog.ID,
+					Author:     worklog.Author.DisplayName,
+					Started:    formatTime(worklog.Started),
+					TimeSpent:  jira.FormatDuration(worklog.Duration()),
+					Visibility: worklog.Visibility.String(),
+					Comment:    string(worklog.Comment),
+				}
+			}
+			if err := reports.GenerateReport(rows, reports.OutputFormat(output)); err != nil {
+				return fmt.Errorf("error generating report: %w", err)
+			}
+			return nil
+		},
+	}
+)
+
+// worklogRow is one worklog in the report of issue worklog list
+type worklogRow struct {
+	ID         string `json:"id"`
+	Author     string `json:"author"`
+	Started    string `json:"started"`
+	TimeSpent  string `json:"timeSpent"`
+	Visibility string `json:"visibility"`
+	Comment    string `json:"comment"`
+}
+
+// parseStarted parses when work started, in local time: a date and time, a
+// date or a time of the day of now
+func parseStarted(value string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid start %q: expected a date and time such as 2006-01-02 15:04, a date or a time", value)
+}
+
+func init() {
+	issueWorklogAddCmd.Flags().StringVar(&worklogTime, "time", "", "Time spent, such as 1h30m or 1d 2h (required)")
+	issueWorklogAddCmd.Flags().StringVar(&worklogStarted, "started", "", "When the work started (default now)")
+	issueWorklogAddCmd.Flags().StringVar(&worklogComment, "comment", "", "Comment in Markdown")
+	issueWorklogAddCmd.Flags().StringVar(&visibilityRole, "role", "", "Restrict the worklog to the members of this project role")
+	issueWorklogAddCmd.Flags().StringVar(&visibilityGroup, "group", "", "Restrict the worklog to the members of this group")
+	issueWorklogAddCmd.MarkFlagsMutuallyExclusive("role", "group")
+	_ = issueWorklogAddCmd.MarkFlagRequired("time")
+
+	issueWorklogListCmd.Flags().IntVarP(&worklogLimit, "limit", "l", 0, "Show at most this many worklogs (default all)")
+
+	issueWorklogCmd.AddCommand(issueWorklogAddCmd)
+	issueWorklogCmd.AddCommand(issueWorklogListCmd)
+	issueCmd.AddCommand(issueWorklogCmd)
+}
@@ -2,18 +2,171 @@ package config
 
 import (
     "fmt"
+    "net/url"
     "os"
+    "path/filepath"
+    "strconv"
     "strings"
+    "time"
 
     "github.com/joho/godotenv"
+    "github.com/spf13/viper"
+)
+
+// Supported authentication types
+const (
+    AuthBearer  = "bearer"  // Personal access token (Server/Data Center)
+    AuthBasic   = "basic"   // Username or email plus API token (Cloud)
+    AuthSession = "session" // Existing session cookie
+    AuthOAuth   = "oauth"   // OAuth 2.0 (3LO) app (Cloud)
+)
+
+// OAuth defaults used when no explicit values are configured
+const (
+    DefaultOAuthRedirectURL = "http://localhost:8765/callback"
+    DefaultOAuthScopes      = "read:jira-work write:jira-work read:jira-user offline_access"
+)
+
+// DefaultAPIVersion is the Jira platform REST API version used unless configured otherwise
+const DefaultAPIVersion = "2"
+
+// DefaultTLSMinVersion is the minimum TLS version accepted unless configured otherwise
+const DefaultTLSMinVersion = "1.2"
+
+// Logging defaults used when OWLIFY_LOG_LEVEL and OWLIFY_LOG_FORMAT are not set
+const (
+    DefaultLogLevel  = "warn"
+    DefaultLogFormat = "text"
 )
 
 // JiraConfig holds all Jira-related configuration
 type JiraConfig struct {
+    // Profile is the name of the profile the configuration was loaded from
+    Profile string
+    // ConfigFile is the configuration file read, empty when there is none
+    ConfigFile string
+
     BaseURL    string
     Token      string
     HTTPProxy  string
     HTTPSProxy string
+
+    // APIVersion is the platform REST API version (2, or 3 on Jira Cloud)
+    APIVersion string
+
+    // AuthType selects how requests are authenticated (bearer, basic or session)
+    AuthType string
+    // Username is used together with Token for basic authentication
+    Username string
+    // SessionCookie holds the cookie(s) sent for session authentication
+    SessionCookie string
+
+    // OAuth 2.0 (3LO) application settings used by the oauth authentication type
+    OAuthClientID     string
+    OAuthClientSecret string
+    OAuthRedirectURL  string
+    OAuthScopes       []string
+
+    // RequestTimeout limits a single HTTP request to Jira (0 uses the default)
+    RequestTimeout time.Duration
+
+    // RetryMaxAttempts is the total number of attempts per request (0 uses the default)
+    RetryMaxAttempts int
+    // RetryMaxWait caps a single wait between attempts (0 uses the default)
+    RetryMaxWait time.Duration
+
+    // RateLimit is the maximum number of requests per second (0 disables rate limiting)
+    RateLimit float64
+    // RateBurst is the number of requests that may be sent at once before the limit applies
+    RateBurst int
+
+    // CABundle is a PEM file of extra certificate authorities, e.g. a corporate CA
+    CABundle string
+    // ClientCert and ClientKey are PEM files used for mutual TLS
+    ClientCert string
+    ClientKey  string
+    // TLSMinVersion is the minimum TLS version accepted (1.0, 1.1, 1.2 or 1.3)
+    TLSMinVersion string
+    // InsecureSkipVerify disables certificate verification, for lab instances only
+    InsecureSkipVerify bool
+
+    // CacheEnabled caches GET responses on disk between runs
+    CacheEnabled bool
+    // CacheTTLs overrides per-endpoint cache lifetimes as "prefix=duration,..."
+    CacheTTLs string
+
+    // LogLevel is the minimum level of log messages written to stderr (debug, info, warn or error)
+    LogLevel string
+    // LogFormat selects text or json log lines
+    LogFormat string
+
+    // Fields maps custom field names such as story_points to field IDs
+    Fields map[string]string
+
+    // DefaultOutput, DefaultProject and DefaultBoard are used when the
+    // --output, --project and --board flags are not given
+    DefaultOutput  string
+    DefaultProject string
+    DefaultBoard   int
+}
+
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
+// Options select the configuration file and profile Initialize loads
+type Options struct {
+    // ConfigFile is the configuration file to read. When empty, OWLIFY_CONFIG
+    // and then config.yaml, config.yml, config.toml or config.json in Dir are used.
+    ConfigFile string
+    // Profile is the profile to use. When empty, OWLIFY_PROFILE and then the
+    // default_profile of the configuration file are used, falling back to DefaultProfile.
+    Profile string
+    // Passphrase returns the passphrase of the credential store. It is only
+    // called when the store holds secrets for the profile. When nil, the
+    // credential store is not read.
+    Passphrase func() (string, error)
+}
+
+// setting is a configuration key together with the environment variable
+// overriding it and its default value
+type setting struct {
+    key          string
+    env          string
+    defaultValue any
+}
+
+// settings lists every key a profile can hold. Keys mirror the environment
+// variables, so JIRA_BASE_URL is base_url in a profile.
+var settings = []setting{
+    {"base_url", "JIRA_BASE_URL", ""},
+    {"token", "JIRA_TOKEN", ""},
+    {"auth_type", "JIRA_AUTH_TYPE", AuthBearer},
+    {"username", "JIRA_USERNAME", ""},
+    {"session_cookie", "JIRA_SESSION_COOKIE", ""},
+    {"api_version", "JIRA_API_VERSION", DefaultAPIVersion},
+    {"oauth_client_id", "JIRA_OAUTH_CLIENT_ID", ""},
+    {"oauth_client_secret", "JIRA_OAUTH_CLIENT_SECRET", ""},
+    {"oauth_redirect_url", "JIRA_OAUTH_REDIRECT_URL", DefaultOAuthRedirectURL},
+    {"oauth_scopes", "JIRA_OAUTH_SCOPES", DefaultOAuthScopes},
+    {"http_proxy", "HTTP_PROXY", ""},
+    {"https_proxy", "HTTPS_PROXY", ""},
+    {"request_timeout", "JIRA_REQUEST_TIMEOUT", "0s"},
+    {"retry_max_attempts", "JIRA_RETRY_MAX_ATTEMPTS", 0},
+    {"retry_max_wait", "JIRA_RETRY_MAX_WAIT", "0s"},
+    {"rate_limit", "JIRA_RATE_LIMIT", 0},
+    {"rate_burst", "JIRA_RATE_BURST", 1},
+    {"ca_bundle", "JIRA_CA_BUNDLE", ""},
+    {"client_cert", "JIRA_CLIENT_CERT", ""},
+    {"client_key", "JIRA_CLIENT_KEY", ""},
+    {"tls_min_version", "JIRA_TLS_MIN_VERSION", DefaultTLSMinVersion},
+    {"insecure_skip_verify", "JIRA_INSECURE_SKIP_VERIFY", false},
+    {"cache", "JIRA_CACHE", true},
+    {"cache_ttls", "JIRA_CACHE_TTLS", ""},
+    {"log_level", "OWLIFY_LOG_LEVEL", DefaultLogLevel},
+    {"log_format", "OWLIFY_LOG_FORMAT", DefaultLogFormat},
+    {"defaults.output", "OWLIFY_OUTPUT", ""},
+    {"defaults.project", "JIRA_PROJECT", ""},
+    {"defaults.board", "JIRA_BOARD", 0},
 }
 
 var (
@@ -21,72 +174,273 @@ var (
     jiraConfig JiraConfig
 )
 
-// Initialize loads configuration from environment variables or .env file
-func Initialize() error {
-    // Check if we're in a test environment
-    if strings.HasSuffix(os.Args[0], ".test") || strings.HasSuffix(os.Args[0], "_test") {
-        // In test environment, use mock values if not set
-        if os.Getenv("JIRA_BASE_URL") == "" {
-            os.Setenv("JIRA_BASE_URL", "https://example.atlassian.net")
+// Initialize loads the configuration. Every setting is taken from the first
+// of these that has it: the environment (including a .env file), the
+// credential store for secrets when opts.Passphrase is set, the selected
+// profile of the configuration file, and the built-in defaults.
+// Command line flags are applied on top by the commands themselves.
+//
+// Initialize does not check that the settings needed to talk to Jira are
+// present, so commands that work offline still run; see Validate.
+func Initialize(opts Options) error {
+    configFile, err := findConfigFile(opts.ConfigFile)
+    if err != nil {
+        return err
+    }
+    if err := loadDotEnv(); err != nil {
+        return err
+    }
+
+    cfg, err := load(configFile, opts.Profile)
+    if err != nil {
+        return err
+    }
+    if opts.Passphrase != nil {
+        if err := applyStoredCredentials(&cfg, opts.Passphrase); err != nil {
+            return err
         }
-        if os.Getenv("JIRA_TOKEN") == "" {
-            os.Setenv("JIRA_TOKEN", "mock-token")
+    }
+
+    jiraConfig = cfg
+    return nil
+}
+
+// Load returns the configuration selected by opts without changing the
+// configuration used by the rest of the process or reading any .env file
+func Load(opts Options) (JiraConfig, error) {
+    configFile, err := findConfigFile(opts.ConfigFile)
+    if err != nil {
+        return JiraConfig{}, err
+    }
+    cfg, err := load(configFile, opts.Profile)
+    if err != nil {
+        return cfg, err
+    }
+    if opts.Passphrase != nil {
+        if err := applyStoredCredentials(&cfg, opts.Passphrase); err != nil {
+            return cfg, err
         }
     }
+    return cfg, Validate(cfg)
+}
 
-    // Try to load .env from current directory first
-    var envPath string
+// MissingSettingsError lists the settings needed to talk to Jira that are
+// not set in the environment, a .env file or the profile
+type MissingSettingsError struct {
+    Profile  string
+    Settings []string
+}
 
-    if err := godotenv.Load(); err != nil {
-        // If .env doesn't exist, try to create one in XDG config dir
-        configDir, err := os.UserConfigDir()
-        if err != nil {
-            return fmt.Errorf("error getting config directory: %v", err)
+func (e *MissingSettingsError) Error() string {
+    names := make([]string, len(e.Settings))
+    for i, key := range e.Settings {
+        names[i] = settingName(key)
+    }
+    return fmt.Sprintf("missing required settings %s: set them in the environment, a .env file or profile %q, or run `owlify config init` to create a profile", strings.Join(names, ", "), e.Profile)
+}
+
+// Validate checks that the settings needed to talk to Jira are present.
+// Every missing setting is reported at once in a *MissingSettingsError.
+func Validate(cfg JiraConfig) error {
+    var missing []string
+    require := func(key, value string) {
+        if value == "" {
+            missing = append(missing, key)
         }
-        
-        appConfigDir := fmt.Sprintf("%s/owlify", configDir)
-        if err := os.MkdirAll(appConfigDir, 0755); err != nil {
-            return fmt.Errorf("error creating config directory: %v", err)
+    }
+
+    require("base_url", cfg.BaseURL)
+    switch cfg.AuthType {
+    case AuthBearer:
+        require("token", cfg.Token)
+    case AuthBasic:
+        require("username", cfg.Username)
+        require("token", cfg.Token)
+    case AuthSession:
+        require("session_cookie", cfg.SessionCookie)
+    case AuthOAuth:
+        require("oauth_client_id", cfg.OAuthClientID)
+        require("oauth_client_secret", cfg.OAuthClientSecret)
+    default:
+        return fmt.Errorf("unsupported JIRA_AUTH_TYPE %q: must be %s, %s, %s or %s", cfg.AuthType, AuthBearer, AuthBasic, AuthSession, AuthOAuth)
+    }
+
+    if len(missing) > 0 {
+        return &MissingSettingsError{Profile: cfg.Profile, Settings: missing}
+    }
+    return nil
+}
+
+// loadDotEnv loads a .env file from the current directory or, failing
+// that, from Dir. Having neither is not an error: the settings may come
+// from the environment or a profile instead.
+func loadDotEnv() error {
+    if err := godotenv.Load(); err == nil {
+        return nil
+    }
+
+    appConfigDir, err := Dir()
+    if err != nil {
+        return err
+    }
+    envPath := filepath.Join(appConfigDir, ".env")
+    if _, err := os.Stat(envPath); os.IsNotExist(err) {
+        return nil
+    }
+    if err := godotenv.Load(envPath); err != nil {
+        return fmt.Errorf("error loading .env file: %v", err)
+    }
+    return nil
+}
+
+// findConfigFile returns the configuration file to read, or "" when there is none
+func findConfigFile(explicit string) (string, error) {
+    if explicit == "" {
+        explicit = os.Getenv("OWLIFY_CONFIG")
+    }
+    if explicit != "" {
+        if _, err := os.Stat(explicit); err != nil {
+            return "", fmt.Errorf("error reading config file: %v", err)
         }
-        
-        envPath = fmt.Sprintf("%s/.env", appConfigDir)
-        
-        // Check if .env already exists in config dir
-        if _, err := os.Stat(envPath); os.IsNotExist(err) {
-            // Create new .env file from example
-            exampleEnv, err := os.ReadFile(".env.example")
-            if err != nil {
-                return fmt.Errorf("error reading .env.example: %v", err)
-            }
-
-            if err := os.WriteFile(envPath, exampleEnv, 0600); err != nil {
-                return fmt.Errorf("error creating .env file: %v", err)
-            }
-
-            fmt.Printf("Created new .env file at %s. Please edit it with your credentials.\n", envPath)
+        return explicit, nil
+    }
+
+    dir, err := Dir()
+    if err != nil {
+        return "", err
+    }
+    for _, ext := range []string{"yaml", "yml", "toml", "json"} {
+        path := filepath.Join(dir, "config."+ext)
+        if _, err := os.Stat(path); err == nil {
+            return path, nil
         }
-        
-        // Try to load the config file we just created/found
-        if err := godotenv.Load(envPath); err != nil {
-            return fmt.Errorf("error loading .env file: %v", err)
+    }
+    return "", nil
+}
+
+// load builds the configuration from the environment and the named profile
+// of configFile, which may be empty
+func load(configFile, profile string) (JiraConfig, error) {
+    file, err := LoadFile(configFile)
+    if err != nil {
+        return JiraConfig{}, err
+    }
+    return file.Config(profile)
+}
+
+// Config returns the configuration of the named profile, or of the profile
+// SelectProfile picks when name is empty, with environment variables applied
+func (f *File) Config(profile string) (JiraConfig, error) {
+    configFile := f.Path
+    profile = f.SelectProfile(profile)
+
+    v := viper.New()
+    for _, s := range settings {
+        v.SetDefault(s.key, s.defaultValue)
+        if err := v.BindEnv(s.key, s.env); err != nil {
+            return JiraConfig{}, err
         }
     }
 
-    // Load environment variables
-    jiraConfig.BaseURL = getEnvOrDefault("JIRA_BASE_URL", "")
-    jiraConfig.Token = getEnvOrDefault("JIRA_TOKEN", "")
-    jiraConfig.HTTPProxy = os.Getenv("HTTP_PROXY")
-    jiraConfig.HTTPSProxy = os.Getenv("HTTPS_PROXY")
-    
-    // Validate required config
-    if jiraConfig.BaseURL == "" {
-        return fmt.Errorf("JIRA_BASE_URL must be set in environment or .env file")
+    if configFile != "" {
+        if !f.HasProfile(profile) {
+            return JiraConfig{}, fmt.Errorf("profile %q not found in %s", profile, configFile)
+        }
+        if err := v.MergeConfigMap(f.Profile(profile)); err != nil {
+            return JiraConfig{}, fmt.Errorf("error reading profile %q: %v", profile, err)
+        }
+    } else if profile != DefaultProfile {
+        return JiraConfig{}, fmt.Errorf("profile %q selected but no config file found", profile)
     }
-    if jiraConfig.Token == "" {
-        return fmt.Errorf("JIRA_TOKEN must be set in environment or .env file")
+
+    cfg := JiraConfig{
+        Profile:           profile,
+        ConfigFile:        configFile,
+        BaseURL:           v.GetString("base_url"),
+        Token:             v.GetString("token"),
+        APIVersion:        v.GetString("api_version"),
+        AuthType:          strings.ToLower(v.GetString("auth_type")),
+        Username:          v.GetString("username"),
+        SessionCookie:     v.GetString("session_cookie"),
+        OAuthClientID:     v.GetString("oauth_client_id"),
+        OAuthClientSecret: v.GetString("oauth_client_secret"),
+        OAuthRedirectURL:  v.GetString("oauth_redirect_url"),
+        OAuthScopes:       v.GetStringSlice("oauth_scopes"),
+        HTTPProxy:         v.GetString("http_proxy"),
+        HTTPSProxy:        v.GetString("https_proxy"),
+        CABundle:          v.GetString("ca_bundle"),
+        ClientCert:        v.GetString("client_cert"),
+        ClientKey:         v.GetString("client_key"),
+        TLSMinVersion:     v.GetString("tls_min_version"),
+        CacheTTLs:         v.GetString("cache_ttls"),
+        LogLevel:          v.GetString("log_level"),
+        LogFormat:         v.GetString("log_format"),
+        Fields:            v.GetStringMapString("fields"),
+        DefaultOutput:     v.GetString("defaults.output"),
+        DefaultProject:    v.GetString("defaults.project"),
     }
-    
-    return nil
+
+    var err error
+    if cfg.RequestTimeout, err = getDuration(v, "request_timeout"); err != nil {
+        return cfg, err
+    }
+    if cfg.RetryMaxAttempts, err = getInt(v, "retry_max_attempts"); err != nil {
+        return cfg, err
+    }
+    if cfg.RetryMaxWait, err = getDuration(v, "retry_max_wait"); err != nil {
+        return cfg, err
+    }
+    if cfg.RateLimit, err = getFloat(v, "rate_limit"); err != nil {
+        return cfg, err
+    }
+    if cfg.RateBurst, err = getInt(v, "rate_burst"); err != nil {
+        return cfg, err
+    }
+    if cfg.InsecureSkipVerify, err = getBool(v, "insecure_skip_verify"); err != nil {
+        return cfg, err
+    }
+    if cfg.CacheEnabled, err = getBool(v, "cache"); err != nil {
+        return cfg, err
+    }
+    if cfg.DefaultBoard, err = getInt(v, "defaults.board"); err != nil {
+        return cfg, err
+    }
+    return cfg, nil
+}
+
+// Dir returns the owlify configuration directory, creating it if needed
+func Dir() (string, error) {
+    configDir, err := os.UserConfigDir()
+    if err != nil {
+        return "", fmt.Errorf("error getting config directory: %v", err)
+    }
+
+    appConfigDir := filepath.Join(configDir, "owlify")
+    if err := os.MkdirAll(appConfigDir, 0755); err != nil {
+        return "", fmt.Errorf("error creating config directory: %v", err)
+    }
+    return appConfigDir, nil
+}
+
+// OAuthTokenPath returns the file OAuth tokens of the current profile are stored in
+func OAuthTokenPath() (string, error) {
+    dir, err := Dir()
+    if err != nil {
+        return "", err
+    }
+    if jiraConfig.Profile == "" || jiraConfig.Profile == DefaultProfile {
+        return filepath.Join(dir, "oauth-token.json"), nil
+    }
+    return filepath.Join(dir, fmt.Sprintf("oauth-token-%s.json", url.PathEscape(jiraConfig.Profile))), nil
+}
+
+// CacheDir returns the directory cached Jira responses are stored in
+func CacheDir() (string, error) {
+    dir, err := Dir()
+    if err != nil {
+        return "", err
+    }
+    return filepath.Join(dir, "cache"), nil
 }
 
 // GetJiraConfig returns the current Jira configuration
@@ -94,11 +448,48 @@ func GetJiraConfig() JiraConfig {
     return jiraConfig
 }
 
-// getEnvOrDefault retrieves an environment variable or returns the default if not set
-func getEnvOrDefault(key, defaultValue string) string {
-    value := os.Getenv(key)
-    if value == "" {
-        return defaultValue
+// settingName describes key for error messages, naming the environment variable too
+func settingName(key string) string {
+    for _, s := range settings {
+        if s.key == key {
+            return fmt.Sprintf("%s (%s)", s.env, key)
+        }
     }
-    return value
-}
\ No newline at end of file
+    return key
+}
+
+// getInt retrieves an integer setting
+func getInt(v *viper.Viper, key string) (int, error) {
+    i, err := strconv.Atoi(v.GetString(key))
+    if err != nil {
+        return 0, fmt.Errorf("%s must be an integer: %v", settingName(key), err)
+    }
+    return i, nil
+}
+
+// getFloat retrieves a floating point setting
+func getFloat(v *viper.Viper, key string) (float64, error) {
+    f, err := strconv.ParseFloat(v.GetString(key), 64)
+    if err != nil {
+        return 0, fmt.Errorf("%s must be a number: %v", settingName(key), err)
+    }
+    return f, nil
+}
+
+// getBool retrieves a boolean setting (e.g. "true" or "1")
+func getBool(v *viper.Viper, key string) (bool, error) {
+    b, err := strconv.ParseBool(v.GetString(key))
+    if err != nil {
+        return false, fmt.Errorf("%s must be true or false: %v", settingName(key), err)
+    }
+    return b, nil
+}
+
+// getDuration retrieves a duration setting (e.g. "30s")
+func getDuration(v *viper.Viper, key string) (time.Duration, error) {
+    d, err := time.ParseDuration(v.GetString(key))
+    if err != nil {
+        return 0, fmt.Errorf("%s must be a duration such as 30s: %v", settingName(key), err)
+    }
+    return d, nil
+}
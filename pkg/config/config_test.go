@@ -0,0 +1,190 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+default_profile: production
+profiles:
+  production:
+    base_url: https://jira.example.com
+    token: prod-token
+    request_timeout: 10s
+    fields:
+      story_points: customfield_10016
+    defaults:
+      output: json
+      board: 42
+  staging:
+    base_url: https://staging.atlassian.net
+    auth_type: Basic
+    username: me@example.com
+    api_version: 3
+    oauth_scopes: [read:jira-work, offline_access]
+`
+
+// writeConfig writes a configuration file with contents named name to a temporary directory
+func writeConfig(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+// clearEnv unsets the environment variables of every setting for the test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, s := range settings {
+		t.Setenv(s.env, "")
+	}
+	t.Setenv("OWLIFY_PROFILE", "")
+}
+
+func TestLoadProfile(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "config.yaml", testConfig)
+
+	cfg, err := load(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "production", cfg.Profile)
+	assert.Equal(t, "https://jira.example.com", cfg.BaseURL)
+	assert.Equal(t, "prod-token", cfg.Token)
+	assert.Equal(t, 10*time.Second, cfg.RequestTimeout)
+	assert.Equal(t, map[string]string{"story_points": "customfield_10016"}, cfg.Fields)
+	assert.Equal(t, "json", cfg.DefaultOutput)
+	assert.Equal(t, 42, cfg.DefaultBoard)
+
+	// Settings missing from the profile keep their defaults
+	assert.Equal(t, AuthBearer, cfg.AuthType)
+	assert.Equal(t, DefaultTLSMinVersion, cfg.TLSMinVersion)
+	assert.True(t, cfg.CacheEnabled)
+	assert.Equal(t, 1, cfg.RateBurst)
+
+	cfg, err = load(path, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.atlassian.net", cfg.BaseURL)
+	assert.Equal(t, AuthBasic, cfg.AuthType)
+	assert.Equal(t, "3", cfg.APIVersion)
+	assert.Equal(t, []string{"read:jira-work", "offline_access"}, cfg.OAuthScopes)
+	assert.Empty(t, cfg.DefaultOutput)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "config.yaml", testConfig)
+
+	t.Setenv("OWLIFY_PROFILE", "staging")
+	t.Setenv("JIRA_BASE_URL", "https://env.example.com")
+	t.Setenv("OWLIFY_OUTPUT", "csv")
+
+	cfg, err := load(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "staging", cfg.Profile)
+	assert.Equal(t, "https://env.example.com", cfg.BaseURL)
+	assert.Equal(t, "me@example.com", cfg.Username)
+	assert.Equal(t, "csv", cfg.DefaultOutput)
+
+	// An explicit profile wins over OWLIFY_PROFILE
+	cfg, err = load(path, "production")
+	require.NoError(t, err)
+	assert.Equal(t, "production", cfg.Profile)
+	assert.Equal(t, "prod-token", cfg.Token)
+}
+
+func TestLoadTOML(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "config.toml", `
+[profiles.default]
+base_url = "https://jira.example.com"
+retry_max_attempts = 2
+`)
+
+	cfg, err := load(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfile, cfg.Profile)
+	assert.Equal(t, "https://jira.example.com", cfg.BaseURL)
+	assert.Equal(t, 2, cfg.RetryMaxAttempts)
+}
+
+func TestLoadWithoutConfigFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("JIRA_BASE_URL", "https://jira.example.com")
+
+	cfg, err := load("", "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProfile, cfg.Profile)
+	assert.Equal(t, "https://jira.example.com", cfg.BaseURL)
+
+	_, err = load("", "staging")
+	assert.ErrorContains(t, err, `profile "staging" selected but no config file found`)
+}
+
+func TestLoadErrors(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "config.yaml", testConfig)
+
+	_, err := load(path, "missing")
+	assert.ErrorContains(t, err, `profile "missing" not found`)
+
+	t.Setenv("JIRA_RETRY_MAX_WAIT", "soon")
+	_, err = load(path, "")
+	assert.ErrorContains(t, err, "JIRA_RETRY_MAX_WAIT (retry_max_wait) must be a duration")
+
+	invalid := writeConfig(t, "config.yaml", "profiles: [")
+	_, err = load(invalid, "")
+	assert.ErrorContains(t, err, "error reading config file")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     JiraConfig
+		missing []string
+	}{
+		{"complete", JiraConfig{BaseURL: "https://jira.example.com", AuthType: AuthBearer, Token: "t"}, nil},
+		{"empty bearer", JiraConfig{AuthType: AuthBearer}, []string{"base_url", "token"}},
+		{"basic without username", JiraConfig{BaseURL: "https://x.atlassian.net", AuthType: AuthBasic, Token: "t"}, []string{"username"}},
+		{"session", JiraConfig{BaseURL: "https://jira.example.com", AuthType: AuthSession}, []string{"session_cookie"}},
+		{"oauth", JiraConfig{AuthType: AuthOAuth}, []string{"base_url", "oauth_client_id", "oauth_client_secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.cfg)
+			if tt.missing == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var missingErr *MissingSettingsError
+			require.ErrorAs(t, err, &missingErr)
+			assert.Equal(t, tt.missing, missingErr.Settings)
+		})
+	}
+
+	err := Validate(JiraConfig{Profile: "work", AuthType: AuthBasic})
+	assert.EqualError(t, err, "missing required settings JIRA_BASE_URL (base_url), JIRA_USERNAME (username), JIRA_TOKEN (token): "+
+		"set them in the environment, a .env file or profile \"work\", or run `owlify config init` to create a profile")
+
+	err = Validate(JiraConfig{BaseURL: "https://jira.example.com", AuthType: "kerberos"})
+	assert.ErrorContains(t, err, `unsupported JIRA_AUTH_TYPE "kerberos"`)
+}
+
+func TestInitializeWithoutSettings(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "config.yaml", "profiles:\n  work:\n    api_version: 3\n")
+
+	require.NoError(t, Initialize(Options{ConfigFile: path, Profile: "work"}))
+	cfg := GetJiraConfig()
+	assert.Empty(t, cfg.BaseURL)
+	assert.Empty(t, cfg.Token)
+
+	var missingErr *MissingSettingsError
+	require.ErrorAs(t, Validate(cfg), &missingErr)
+	assert.Equal(t, []string{"base_url", "token"}, missingErr.Settings)
+}
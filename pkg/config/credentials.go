@@ -0,0 +1,420 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters for new credential stores, as recommended for
+// interactive logins. They are stored in the file, so they can be raised
+// later without breaking existing stores.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// credentialsVersion is the format version of the credentials file
+const credentialsVersion = 1
+
+// ErrWrongPassphrase is returned when the credential store cannot be
+// decrypted with the given passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase for the credential store")
+
+// ErrStoreLocked is returned when secrets are read or written before the
+// credential store is unlocked
+var ErrStoreLocked = errors.New("credential store is locked")
+
+// CredentialStore keeps the secrets of each profile, such as its token,
+// encrypted at rest. The key is derived from a passphrase with scrypt and
+// every profile is sealed separately with AES-256-GCM, so profiles can be
+// listed and removed without the passphrase.
+type CredentialStore struct {
+	Path string
+
+	file credentialsFile
+	key  []byte
+	// secrets holds the decrypted secrets of profiles read or changed
+	// since the store was unlocked
+	secrets map[string]map[string]string
+	dirty   map[string]bool
+}
+
+// credentialsFile is the on-disk format of the credential store
+type credentialsFile struct {
+	Version  int                      `json:"version"`
+	KDF      kdfParams                `json:"kdf"`
+	Profiles map[string]sealedSecrets `json:"profiles"`
+}
+
+// kdfParams are the scrypt parameters the key is derived with
+type kdfParams struct {
+	Name string `json:"name"`
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// sealedSecrets are the secrets of one profile, encrypted as JSON
+type sealedSecrets struct {
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// CredentialsFile returns where the credential store is kept
+func CredentialsFile() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials.json"), nil
+}
+
+// LoadCredentialStore reads the credential store at path without
+// decrypting it. A missing file results in an empty store.
+func LoadCredentialStore(path string) (*CredentialStore, error) {
+	s := &CredentialStore{
+		Path:    path,
+		secrets: map[string]map[string]string{},
+		dirty:   map[string]bool{},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.file = credentialsFile{Version: credentialsVersion, Profiles: map[string]sealedSecrets{}}
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading credential store: %v", err)
+	}
+	if err := json.Unmarshal(data, &s.file); err != nil {
+		return nil, fmt.Errorf("error reading credential store %s: %v", path, err)
+	}
+	if s.file.Version != credentialsVersion || s.file.KDF.Name != "scrypt" {
+		return nil, fmt.Errorf("unsupported credential store %s: version %d, key derivation %q", path, s.file.Version, s.file.KDF.Name)
+	}
+	if s.file.Profiles == nil {
+		s.file.Profiles = map[string]sealedSecrets{}
+	}
+	return s, nil
+}
+
+// Exists reports whether the store has been saved before
+func (s *CredentialStore) Exists() bool {
+	return s.file.KDF.Salt != nil
+}
+
+// Profiles returns the names of the profiles with stored secrets, sorted
+func (s *CredentialStore) Profiles() []string {
+	var names []string
+	for name := range s.file.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasProfile reports whether secrets are stored for the named profile
+func (s *CredentialStore) HasProfile(name string) bool {
+	_, ok := s.file.Profiles[name]
+	return ok
+}
+
+// Unlock derives the key from passphrase. For an existing store the
+// passphrase is checked by decrypting one of its profiles.
+func (s *CredentialStore) Unlock(passphrase string) error {
+	if passphrase == "" {
+		return errors.New("the credential store passphrase must not be empty")
+	}
+	if !s.Exists() {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		s.file.KDF = kdfParams{Name: "scrypt", Salt: salt, N: scryptN, R: scryptR, P: scryptP}
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), s.file.KDF.Salt, s.file.KDF.N, s.file.KDF.R, s.file.KDF.P, scryptKeyLen)
+	if err != nil {
+		return fmt.Errorf("error deriving credential store key: %v", err)
+	}
+	s.key = key
+
+	for _, name := range s.Profiles() {
+		if _, err := s.open(name); err != nil {
+			s.key = nil
+			return err
+		}
+		break
+	}
+	return nil
+}
+
+// Get returns the secret stored under key for the named profile
+func (s *CredentialStore) Get(profile, key string) (string, bool, error) {
+	secrets, err := s.open(profile)
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := secrets[key]
+	return value, ok, nil
+}
+
+// Set stores a secret for the named profile. Only secret settings such as
+// token can be stored.
+func (s *CredentialStore) Set(profile, key, value string) error {
+	if !IsSecret(key) {
+		return fmt.Errorf("%s is not a secret setting; secrets are %s", key, secretNames())
+	}
+	secrets, err := s.open(profile)
+	if err != nil {
+		return err
+	}
+	secrets[key] = value
+	s.dirty[profile] = true
+	return nil
+}
+
+// Remove deletes the secret stored under key for the named profile, or
+// every secret of the profile when key is empty, and reports whether
+// anything was stored. Removing a whole profile does not need the store
+// to be unlocked.
+func (s *CredentialStore) Remove(profile, key string) (bool, error) {
+	if !s.HasProfile(profile) {
+		return false, nil
+	}
+	if key == "" {
+		delete(s.file.Profiles, profile)
+		delete(s.secrets, profile)
+		delete(s.dirty, profile)
+		return true, nil
+	}
+
+	secrets, err := s.open(profile)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := secrets[key]; !ok {
+		return false, nil
+	}
+	delete(secrets, key)
+	s.dirty[profile] = true
+	return true, nil
+}
+
+// Apply copies the stored secrets of cfg's profile into cfg. Secrets set in
+// the environment take precedence and are left alone.
+func (s *CredentialStore) Apply(cfg *JiraConfig) error {
+	if !s.HasProfile(cfg.Profile) {
+		return nil
+	}
+	for key, target := range secretFields(cfg) {
+		if os.Getenv(envName(key)) != "" {
+			continue
+		}
+		value, ok, err := s.Get(cfg.Profile, key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			*target = value
+		}
+	}
+	return nil
+}
+
+// SetSecret sets the secret setting key of cfg, e.g. a token entered by the
+// user before it is stored
+func SetSecret(cfg *JiraConfig, key, value string) error {
+	target, ok := secretFields(cfg)[key]
+	if !ok {
+		return fmt.Errorf("%s is not a secret setting; secrets are %s", key, secretNames())
+	}
+	*target = value
+	return nil
+}
+
+// secretFields returns the fields of cfg holding the secret settings
+func secretFields(cfg *JiraConfig) map[string]*string {
+	return map[string]*string{
+		"token":               &cfg.Token,
+		"session_cookie":      &cfg.SessionCookie,
+		"oauth_client_secret": &cfg.OAuthClientSecret,
+	}
+}
+
+// Save seals the changed profiles and writes the store, readable only by
+// the current user
+func (s *CredentialStore) Save() error {
+	for profile := range s.dirty {
+		secrets := s.secrets[profile]
+		if len(secrets) == 0 {
+			delete(s.file.Profiles, profile)
+			continue
+		}
+		sealed, err := s.seal(profile, secrets)
+		if err != nil {
+			return err
+		}
+		s.file.Profiles[profile] = sealed
+	}
+	s.dirty = map[string]bool{}
+
+	data, err := json.MarshalIndent(s.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return fmt.Errorf("error creating config directory: %v", err)
+	}
+
+	// Replace the store atomically so an interrupted write cannot lose it
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), ".credentials-*")
+	if err != nil {
+		return fmt.Errorf("error writing credential store: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing credential store: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing credential store: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("error writing credential store: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return fmt.Errorf("error writing credential store: %v", err)
+	}
+	return nil
+}
+
+// open returns the decrypted secrets of the named profile, an empty map
+// when none are stored
+func (s *CredentialStore) open(profile string) (map[string]string, error) {
+	if secrets, ok := s.secrets[profile]; ok {
+		return secrets, nil
+	}
+	if s.key == nil {
+		return nil, ErrStoreLocked
+	}
+
+	secrets := map[string]string{}
+	if sealed, ok := s.file.Profiles[profile]; ok {
+		aead, err := s.aead()
+		if err != nil {
+			return nil, err
+		}
+		// The profile name is authenticated so sealed secrets cannot be
+		// moved to another profile
+		plaintext, err := aead.Open(nil, sealed.Nonce, sealed.Ciphertext, []byte(profile))
+		if err != nil {
+			return nil, ErrWrongPassphrase
+		}
+		if err := json.Unmarshal(plaintext, &secrets); err != nil {
+			return nil, fmt.Errorf("error reading secrets of profile %q: %v", profile, err)
+		}
+	}
+	s.secrets[profile] = secrets
+	return secrets, nil
+}
+
+// seal encrypts the secrets of the named profile with a fresh nonce
+func (s *CredentialStore) seal(profile string, secrets map[string]string) (sealedSecrets, error) {
+	if s.key == nil {
+		return sealedSecrets{}, ErrStoreLocked
+	}
+	aead, err := s.aead()
+	if err != nil {
+		return sealedSecrets{}, err
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return sealedSecrets{}, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return sealedSecrets{}, err
+	}
+	return sealedSecrets{Nonce: nonce, Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(profile))}, nil
+}
+
+func (s *CredentialStore) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// applyStoredCredentials fills in the secrets of cfg from the credential
+// store. The passphrase is only asked for when the store holds secrets for
+// the profile that the environment does not override.
+func applyStoredCredentials(cfg *JiraConfig, passphrase func() (string, error)) error {
+	path, err := CredentialsFile()
+	if err != nil {
+		return err
+	}
+	store, err := LoadCredentialStore(path)
+	if err != nil {
+		return err
+	}
+	// Do not ask for the passphrase when the environment has the secret
+	if !store.HasProfile(cfg.Profile) || os.Getenv(envName(authSecret(cfg.AuthType))) != "" {
+		return nil
+	}
+
+	pass, err := passphrase()
+	if err != nil {
+		return err
+	}
+	if err := store.Unlock(pass); err != nil {
+		return err
+	}
+	return store.Apply(cfg)
+}
+
+// authSecret returns the secret setting the authentication type needs
+func authSecret(authType string) string {
+	switch authType {
+	case AuthSession:
+		return "session_cookie"
+	case AuthOAuth:
+		return "oauth_client_secret"
+	default:
+		return "token"
+	}
+}
+
+// envName returns the environment variable overriding key
+func envName(key string) string {
+	for _, s := range settings {
+		if s.key == key {
+			return s.env
+		}
+	}
+	return ""
+}
+
+// secretNames lists the secret settings for error messages
+func secretNames() string {
+	var names []string
+	for key := range secretKeys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore returns an unlocked store holding a token for the work profile
+func newTestStore(t *testing.T) *CredentialStore {
+	t.Helper()
+	store, err := LoadCredentialStore(filepath.Join(t.TempDir(), "credentials.json"))
+	require.NoError(t, err)
+	require.NoError(t, store.Unlock("correct horse"))
+	require.NoError(t, store.Set("work", "token", "s3cret-token"))
+	require.NoError(t, store.Save())
+	return store
+}
+
+func TestCredentialStoreRoundTrip(t *testing.T) {
+	store := newTestStore(t)
+
+	info, err := os.Stat(store.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(store.Path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cret-token")
+
+	reloaded, err := LoadCredentialStore(store.Path)
+	require.NoError(t, err)
+	assert.True(t, reloaded.Exists())
+	assert.Equal(t, []string{"work"}, reloaded.Profiles())
+
+	_, _, err = reloaded.Get("work", "token")
+	assert.ErrorIs(t, err, ErrStoreLocked)
+
+	require.NoError(t, reloaded.Unlock("correct horse"))
+	token, ok, err := reloaded.Get("work", "token")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "s3cret-token", token)
+}
+
+func TestCredentialStoreWrongPassphrase(t *testing.T) {
+	store := newTestStore(t)
+
+	reloaded, err := LoadCredentialStore(store.Path)
+	require.NoError(t, err)
+	assert.ErrorIs(t, reloaded.Unlock("battery staple"), ErrWrongPassphrase)
+	assert.Error(t, reloaded.Unlock(""))
+}
+
+func TestCredentialStoreSetRejectsSettings(t *testing.T) {
+	store := newTestStore(t)
+	assert.ErrorContains(t, store.Set("work", "base_url", "https://jira.example.com"), "not a secret setting")
+}
+
+func TestSetSecret(t *testing.T) {
+	var cfg JiraConfig
+	require.NoError(t, SetSecret(&cfg, "token", "abc"))
+	require.NoError(t, SetSecret(&cfg, "oauth_client_secret", "xyz"))
+	assert.Equal(t, "abc", cfg.Token)
+	assert.Equal(t, "xyz", cfg.OAuthClientSecret)
+	assert.ErrorContains(t, SetSecret(&cfg, "username", "jdoe"), "not a secret setting")
+}
+
+func TestCredentialStoreRemove(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.Set("work", "session_cookie", "JSESSIONID=abc"))
+	require.NoError(t, store.Save())
+
+	removed, err := store.Remove("work", "token")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = store.Remove("work", "token")
+	require.NoError(t, err)
+	assert.False(t, removed)
+	require.NoError(t, store.Save())
+
+	// Whole profiles are removed without the passphrase
+	locked, err := LoadCredentialStore(store.Path)
+	require.NoError(t, err)
+	removed, err = locked.Remove("work", "")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	require.NoError(t, locked.Save())
+
+	reloaded, err := LoadCredentialStore(store.Path)
+	require.NoError(t, err)
+	assert.Empty(t, reloaded.Profiles())
+}
+
+func TestLoadWithCredentialStore(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	path := writeConfig(t, "config.yaml", testConfig)
+
+	credentials, err := CredentialsFile()
+	require.NoError(t, err)
+	store, err := LoadCredentialStore(credentials)
+	require.NoError(t, err)
+	require.NoError(t, store.Unlock("correct horse"))
+	require.NoError(t, store.Set("staging", "token", "stored-token"))
+	require.NoError(t, store.Save())
+
+	asked := 0
+	passphrase := func() (string, error) {
+		asked++
+		return "correct horse", nil
+	}
+
+	cfg, err := Load(Options{ConfigFile: path, Profile: "staging", Passphrase: passphrase})
+	require.NoError(t, err)
+	assert.Equal(t, "stored-token", cfg.Token)
+	assert.Equal(t, 1, asked)
+
+	// Profiles without stored secrets do not need the passphrase
+	cfg, err = Load(Options{ConfigFile: path, Profile: "production", Passphrase: passphrase})
+	require.NoError(t, err)
+	assert.Equal(t, "prod-token", cfg.Token)
+	assert.Equal(t, 1, asked)
+
+	// Secrets in the environment win without unlocking the store
+	t.Setenv("JIRA_TOKEN", "env-token")
+	cfg, err = Load(Options{ConfigFile: path, Profile: "staging", Passphrase: passphrase})
+	require.NoError(t, err)
+	assert.Equal(t, "env-token", cfg.Token)
+	assert.Equal(t, 1, asked)
+
+	t.Setenv("JIRA_TOKEN", "")
+	_, err = Load(Options{ConfigFile: path, Profile: "staging", Passphrase: func() (string, error) {
+		return "", errors.New("no terminal")
+	}})
+	assert.ErrorContains(t, err, "no terminal")
+}
@@ -0,0 +1,316 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// secretKeys are settings whose values are masked when listed
+var secretKeys = map[string]bool{
+	"token":               true,
+	"session_cookie":      true,
+	"oauth_client_secret": true,
+}
+
+// File is a configuration file holding named profiles. Keys are stored in
+// lower case, as viper reads them.
+type File struct {
+	Path   string
+	values map[string]any
+}
+
+// DefaultConfigFile returns where a new configuration file is created
+func DefaultConfigFile() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// ConfigFilePath returns the configuration file selected by explicit or
+// OWLIFY_CONFIG, else the existing file in Dir, else DefaultConfigFile
+func ConfigFilePath(explicit string) (string, error) {
+	if explicit == "" {
+		explicit = os.Getenv("OWLIFY_CONFIG")
+	}
+	if explicit != "" {
+		return explicit, nil
+	}
+	path, err := findConfigFile("")
+	if err != nil || path != "" {
+		return path, err
+	}
+	return DefaultConfigFile()
+}
+
+// LoadFile reads the configuration file at path. A missing file, or an
+// empty path, results in an empty File that can still be saved.
+func LoadFile(path string) (*File, error) {
+	f := &File{Path: path, values: map[string]any{}}
+	if path == "" {
+		return f, nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %v", path, err)
+	}
+	f.values = v.AllSettings()
+	return f, nil
+}
+
+// DefaultProfile returns the profile used when none is selected
+func (f *File) DefaultProfile() string {
+	if name, ok := f.values["default_profile"].(string); ok && name != "" {
+		return name
+	}
+	return DefaultProfile
+}
+
+// SetDefaultProfile makes name the profile used when none is selected
+func (f *File) SetDefaultProfile(name string) {
+	f.values["default_profile"] = name
+}
+
+// Profiles returns the names of the profiles in the file, sorted
+func (f *File) Profiles() []string {
+	var names []string
+	for name := range f.profiles() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasProfile reports whether the file holds the named profile
+func (f *File) HasProfile(name string) bool {
+	_, ok := f.profiles()[name]
+	return ok
+}
+
+// Profile returns the settings of the named profile, nil if it does not exist
+func (f *File) Profile(name string) map[string]any {
+	profile, _ := f.profiles()[name].(map[string]any)
+	return profile
+}
+
+// Get returns the value of key in the named profile
+func (f *File) Get(profile, key string) (any, bool) {
+	var value any = f.Profile(profile)
+	for _, part := range strings.Split(strings.ToLower(key), ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Set stores value under key in the named profile, creating it if needed
+func (f *File) Set(profile, key, value string) error {
+	key = strings.ToLower(key)
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+
+	profiles := f.profiles()
+	if profiles == nil {
+		profiles = map[string]any{}
+		f.values["profiles"] = profiles
+	}
+	m, _ := profiles[profile].(map[string]any)
+	if m == nil {
+		m = map[string]any{}
+		profiles[profile] = m
+	}
+
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, _ := m[part].(map[string]any)
+		if next == nil {
+			next = map[string]any{}
+			m[part] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = value
+	return nil
+}
+
+// Unset removes key from the named profile and reports whether it was set.
+// Sections left empty are removed along with it.
+func (f *File) Unset(profile, key string) bool {
+	return unset(f.Profile(profile), strings.Split(strings.ToLower(key), "."))
+}
+
+// unset removes the key at path from m, dropping maps that become empty
+func unset(m map[string]any, path []string) bool {
+	if m == nil {
+		return false
+	}
+	if len(path) == 1 {
+		_, ok := m[path[0]]
+		delete(m, path[0])
+		return ok
+	}
+	child, _ := m[path[0]].(map[string]any)
+	removed := unset(child, path[1:])
+	if removed && len(child) == 0 {
+		delete(m, path[0])
+	}
+	return removed
+}
+
+// Save writes the file, readable only by the current user since profiles
+// hold credentials. The format follows the file extension.
+func (f *File) Save() error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0700); err != nil {
+		return fmt.Errorf("error creating config directory: %v", err)
+	}
+
+	// Create the file with restricted permissions before viper writes to it
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing config file: %v", err)
+	}
+	file.Close()
+	if err := os.Chmod(f.Path, 0600); err != nil {
+		return fmt.Errorf("error writing config file: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigPermissions(0600)
+	if err := v.MergeConfigMap(f.values); err != nil {
+		return err
+	}
+	if err := v.WriteConfigAs(f.Path); err != nil {
+		return fmt.Errorf("error writing config file %s: %v", f.Path, err)
+	}
+	return nil
+}
+
+func (f *File) profiles() map[string]any {
+	profiles, _ := f.values["profiles"].(map[string]any)
+	return profiles
+}
+
+// SelectProfile returns the profile to use: explicit, else OWLIFY_PROFILE,
+// else the default profile of the file
+func (f *File) SelectProfile(explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if env := os.Getenv("OWLIFY_PROFILE"); env != "" {
+		return env
+	}
+	return f.DefaultProfile()
+}
+
+// Keys returns every setting a profile can hold, sorted
+func Keys() []string {
+	var keys []string
+	for _, s := range settings {
+		keys = append(keys, s.key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateKey checks that key is a known setting or a field mapping
+func ValidateKey(key string) error {
+	if name, ok := strings.CutPrefix(key, "fields."); ok && name != "" && !strings.Contains(name, ".") {
+		return nil
+	}
+	for _, s := range settings {
+		if s.key == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown setting %q; valid settings are %s and fields.<name>", key, strings.Join(Keys(), ", "))
+}
+
+// IsSecret reports whether the value of key should be masked when shown
+func IsSecret(key string) bool {
+	return secretKeys[key]
+}
+
+// Sources a setting's effective value can come from
+const (
+	SourceEnv     = "env"
+	SourceProfile = "profile"
+	SourceDefault = "default"
+)
+
+// Value is the effective value of a setting and where it came from
+type Value struct {
+	Key    string
+	Env    string
+	Value  string
+	Source string
+}
+
+// Values returns the effective value of every setting of the selected
+// profile, including its field mappings
+func Values(opts Options) ([]Value, error) {
+	path, err := findConfigFile(opts.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	file, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profile := file.SelectProfile(opts.Profile)
+
+	var values []Value
+	for _, s := range settings {
+		value := Value{Key: s.key, Env: s.env, Value: fmt.Sprint(s.defaultValue), Source: SourceDefault}
+		if env := os.Getenv(s.env); env != "" {
+			value.Value, value.Source = env, SourceEnv
+		} else if v, ok := file.Get(profile, s.key); ok {
+			value.Value, value.Source = formatValue(v), SourceProfile
+		}
+		values = append(values, value)
+	}
+
+	if fields, ok := file.Get(profile, "fields"); ok {
+		if m, ok := fields.(map[string]any); ok {
+			var names []string
+			for name := range m {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				values = append(values, Value{Key: "fields." + name, Value: formatValue(m[name]), Source: SourceProfile})
+			}
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values, nil
+}
+
+// formatValue formats a value read from a configuration file
+func formatValue(value any) string {
+	if list, ok := value.([]any); ok {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, " ")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSetAndSave(t *testing.T) {
+	clearEnv(t)
+	path := filepath.Join(t.TempDir(), "owlify", "config.yaml")
+
+	file, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, file.Profiles())
+
+	require.NoError(t, file.Set("work", "base_url", "https://jira.example.com"))
+	require.NoError(t, file.Set("work", "token", "secret"))
+	require.NoError(t, file.Set("work", "Fields.Story_Points", "customfield_10016"))
+	file.SetDefaultProfile("work")
+	require.NoError(t, file.Save())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	cfg, err := Load(Options{ConfigFile: path})
+	require.NoError(t, err)
+	assert.Equal(t, "work", cfg.Profile)
+	assert.Equal(t, "https://jira.example.com", cfg.BaseURL)
+	assert.Equal(t, "secret", cfg.Token)
+	assert.Equal(t, map[string]string{"story_points": "customfield_10016"}, cfg.Fields)
+}
+
+func TestFileSetUnknownKey(t *testing.T) {
+	file, err := LoadFile("")
+	require.NoError(t, err)
+
+	err = file.Set("default", "base_uri", "https://jira.example.com")
+	assert.ErrorContains(t, err, `unknown setting "base_uri"`)
+	assert.False(t, file.HasProfile("default"))
+}
+
+func TestFileUnset(t *testing.T) {
+	file, err := LoadFile(writeConfig(t, "config.yaml", testConfig))
+	require.NoError(t, err)
+
+	assert.True(t, file.Unset("production", "fields.story_points"))
+	_, ok := file.Get("production", "fields")
+	assert.False(t, ok, "empty sections are removed")
+
+	assert.False(t, file.Unset("production", "fields.story_points"))
+	assert.False(t, file.Unset("missing", "token"))
+
+	value, ok := file.Get("production", "defaults.board")
+	require.True(t, ok)
+	assert.Equal(t, 42, value)
+}
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"base_url", "defaults.output", "fields.epic_link"} {
+		assert.NoError(t, ValidateKey(key), key)
+	}
+	for _, key := range []string{"", "profiles", "fields.", "fields.a.b", "defaults"} {
+		assert.Error(t, ValidateKey(key), key)
+	}
+}
+
+func TestValues(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, "config.yaml", testConfig)
+	t.Setenv("JIRA_REQUEST_TIMEOUT", "5s")
+
+	values, err := Values(Options{ConfigFile: path})
+	require.NoError(t, err)
+
+	byKey := map[string]Value{}
+	for _, value := range values {
+		byKey[value.Key] = value
+	}
+	assert.Equal(t, Value{Key: "base_url", Env: "JIRA_BASE_URL", Value: "https://jira.example.com", Source: SourceProfile}, byKey["base_url"])
+	assert.Equal(t, Value{Key: "request_timeout", Env: "JIRA_REQUEST_TIMEOUT", Value: "5s", Source: SourceEnv}, byKey["request_timeout"])
+	assert.Equal(t, SourceDefault, byKey["auth_type"].Source)
+	assert.Equal(t, Value{Key: "fields.story_points", Value: "customfield_10016", Source: SourceProfile}, byKey["fields.story_points"])
+	assert.True(t, IsSecret("token"))
+	assert.False(t, IsSecret("base_url"))
+}
@@ -0,0 +1,701 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ADFNode is a node of an Atlassian Document Format (ADF) document, the
+// rich text format REST API v3 uses for descriptions and comments
+type ADFNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Marks   []ADFMark      `json:"marks,omitempty"`
+	Content []ADFNode      `json:"content,omitempty"`
+}
+
+// ADFMark is a formatting mark applied to an ADF text node
+type ADFMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Jira rejects documents without a
+// content array, so it is always written for the root node.
+func (n ADFNode) MarshalJSON() ([]byte, error) {
+	type node ADFNode
+	if n.Type != "doc" {
+		return json.Marshal(node(n))
+	}
+	content := n.Content
+	if content == nil {
+		content = []ADFNode{}
+	}
+	return json.Marshal(struct {
+		node
+		Content []ADFNode `json:"content"`
+	}{node(n), content})
+}
+
+// attr returns the attribute name of n formatted as a string
+func (n ADFNode) attr(name string) string {
+	value, ok := n.Attrs[name]
+	if !ok || value == nil {
+		return ""
+	}
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// RichText is the body of a description or comment. API v2 returns it as a
+// string in Jira wiki markup and v3 as an ADF document, which is converted
+// to Markdown while decoding so both versions read the same way.
+type RichText string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *RichText) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		*t = RichText(text)
+		return nil
+	}
+
+	var doc ADFNode
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("error decoding ADF document: %w", err)
+	}
+	*t = RichText(ADFToMarkdown(&doc))
+	return nil
+}
+
+// ADFToMarkdown renders an ADF document as Markdown
+func ADFToMarkdown(doc *ADFNode) string {
+	return adfRenderer{markdown: true}.render(doc)
+}
+
+// ADFToText renders an ADF document as plain text
+func ADFToText(doc *ADFNode) string {
+	return adfRenderer{}.render(doc)
+}
+
+// adfRenderer converts ADF to Markdown or, without markdown, to plain text.
+// Unknown nodes are rendered through their content so no text is lost.
+type adfRenderer struct {
+	markdown bool
+}
+
+func (r adfRenderer) render(doc *ADFNode) string {
+	if doc == nil {
+		return ""
+	}
+	if doc.Type != "doc" {
+		return strings.TrimSpace(r.blocks([]ADFNode{*doc}))
+	}
+	return strings.TrimSpace(r.blocks(doc.Content))
+}
+
+// blocks renders block nodes separated by blank lines
+func (r adfRenderer) blocks(nodes []ADFNode) string {
+	var parts []string
+	for _, node := range nodes {
+		if block := r.block(node); block != "" {
+			parts = append(parts, block)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (r adfRenderer) block(n ADFNode) string {
+	switch n.Type {
+	case "paragraph":
+		return r.inline(n.Content)
+	case "heading":
+		text := r.inline(n.Content)
+		if !r.markdown {
+			return text
+		}
+		level, _ := strconv.Atoi(n.attr("level"))
+		return strings.Repeat("#", min(max(level, 1), 6)) + " " + text
+	case "bulletList", "orderedList":
+		return r.list(n)
+	case "codeBlock":
+		text := r.inline(n.Content)
+		if !r.markdown {
+			return text
+		}
+		return "```" + n.attr("language") + "\n" + text + "\n```"
+	case "blockquote", "panel":
+		return prefixLines(r.blocks(n.Content), "> ")
+	case "rule":
+		return "---"
+	case "table":
+		return r.table(n)
+	case "mediaSingle", "mediaGroup", "media":
+		return ""
+	default:
+		if len(n.Content) > 0 && isBlockNode(n.Content[0]) {
+			return r.blocks(n.Content)
+		}
+		return r.inline([]ADFNode{n})
+	}
+}
+
+// list renders a bullet or ordered list, indenting nested content under its marker
+func (r adfRenderer) list(n ADFNode) string {
+	number := 1
+	if order, err := strconv.Atoi(n.attr("order")); err == nil {
+		number = order
+	}
+
+	var lines []string
+	for _, item := range n.Content {
+		marker := "- "
+		if n.Type == "orderedList" {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		var parts []string
+		for _, child := range item.Content {
+			if block := r.block(child); block != "" {
+				parts = append(parts, block)
+			}
+		}
+		body := strings.Join(parts, "\n")
+		indent := strings.Repeat(" ", len(marker))
+		lines = append(lines, marker+strings.ReplaceAll(body, "\n", "\n"+indent))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// table renders a table, using the first row as header in Markdown
+func (r adfRenderer) table(n ADFNode) string {
+	var lines []string
+	for i, row := range n.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			text := strings.ReplaceAll(r.blocks(cell.Content), "\n", " ")
+			if r.markdown {
+				text = strings.ReplaceAll(text, "|", `\|`)
+			}
+			cells = append(cells, text)
+		}
+
+		if !r.markdown {
+			lines = append(lines, strings.Join(cells, " | "))
+			continue
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inline renders inline nodes such as text, mentions and hard breaks
+func (r adfRenderer) inline(nodes []ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(r.text(n))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			text := n.attr("text")
+			if !strings.HasPrefix(text, "@") {
+				text = "@" + text
+			}
+			b.WriteString(text)
+		case "emoji":
+			if text := n.attr("text"); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(n.attr("shortName"))
+			}
+		case "inlineCard", "blockCard":
+			b.WriteString(n.attr("url"))
+		case "status":
+			b.WriteString(n.attr("text"))
+		case "date":
+			b.WriteString(n.attr("timestamp"))
+		default:
+			b.WriteString(r.inline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+// text renders a text node with its marks
+func (r adfRenderer) text(n ADFNode) string {
+	text := n.Text
+	var href string
+	for _, mark := range n.Marks {
+		if mark.Type == "link" {
+			href = fmt.Sprint(mark.Attrs["href"])
+		}
+		if !r.markdown {
+			continue
+		}
+		switch mark.Type {
+		case "code":
+			text = "`" + text + "`"
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "strike":
+			text = "~~" + text + "~~"
+		}
+	}
+
+	switch {
+	case href == "":
+		return text
+	case r.markdown:
+		return "[" + text + "](" + href + ")"
+	case text == href:
+		return text
+	default:
+		return text + " (" + href + ")"
+	}
+}
+
+// isBlockNode reports whether n is rendered as a block of its own
+func isBlockNode(n ADFNode) bool {
+	switch n.Type {
+	case "paragraph", "heading", "bulletList", "orderedList", "codeBlock",
+		"blockquote", "panel", "rule", "table", "mediaSingle", "mediaGroup":
+		return true
+	}
+	return false
+}
+
+// prefixLines adds prefix to every line of text
+func prefixLines(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(prefix+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRule      = regexp.MustCompile(`^(?:-[ \t]*){3,}$|^(?:\*[ \t]*){3,}$|^(?:_[ \t]*){3,}$`)
+	mdListItem  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdTableSep  = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+	mdFence     = regexp.MustCompile("^(```|~~~)\\s*(\\S*)")
+	mdOrdered   = regexp.MustCompile(`^\d+`)
+	mdAutolink  = regexp.MustCompile(`^<(https?://[^>\s]+)>`)
+	mdLinkStart = regexp.MustCompile(`^\[([^\]]*)\]\(([^)\s]+)\)`)
+)
+
+// MarkdownToADF converts Markdown into an ADF document. It understands the
+// commonly used subset: headings, paragraphs, emphasis, strike-through,
+// inline code, links, bullet and ordered lists, code blocks, block quotes,
+// rules and tables. Line breaks inside a paragraph are kept as hard breaks,
+// matching how text typed into Jira's own editor is stored.
+func MarkdownToADF(markdown string) *ADFNode {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	return &ADFNode{Type: "doc", Version: 1, Content: parseBlocks(lines)}
+}
+
+// parseBlocks converts lines of Markdown into block nodes
+func parseBlocks(lines []string) []ADFNode {
+	var blocks []ADFNode
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case trimmed == "":
+			i++
+		case mdFence.MatchString(trimmed):
+			var node ADFNode
+			node, i = parseCodeBlock(lines, i)
+			blocks = append(blocks, node)
+		case mdHeading.MatchString(trimmed):
+			m := mdHeading.FindStringSubmatch(trimmed)
+			blocks = append(blocks, ADFNode{
+				Type:    "heading",
+				Attrs:   map[string]any{"level": len(m[1])},
+				Content: parseInline(m[2], nil),
+			})
+			i++
+		case mdRule.MatchString(trimmed):
+			blocks = append(blocks, ADFNode{Type: "rule"})
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				line := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(line, " "))
+			}
+			blocks = append(blocks, ADFNode{Type: "blockquote", Content: parseBlocks(quoted)})
+		case mdListItem.MatchString(lines[i]):
+			var node ADFNode
+			node, i = parseList(lines, i)
+			blocks = append(blocks, node)
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && mdTableSep.MatchString(strings.TrimSpace(lines[i+1])):
+			var node ADFNode
+			node, i = parseTable(lines, i)
+			blocks = append(blocks, node)
+		default:
+			var paragraph []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(paragraph) == 0 || !isBlockStart(lines[i])); i++ {
+				paragraph = append(paragraph, lines[i])
+			}
+			blocks = append(blocks, ADFNode{Type: "paragraph", Content: parseParagraph(paragraph)})
+		}
+	}
+	return blocks
+}
+
+// isBlockStart reports whether line starts a block other than a paragraph
+func isBlockStart(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return mdFence.MatchString(trimmed) || mdHeading.MatchString(trimmed) || mdRule.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, ">") || mdListItem.MatchString(line)
+}
+
+// parseCodeBlock parses a fenced code block starting at lines[start]
+func parseCodeBlock(lines []string, start int) (ADFNode, int) {
+	m := mdFence.FindStringSubmatch(strings.TrimSpace(lines[start]))
+	fence := m[1]
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+		code = append(code, lines[i])
+	}
+
+	node := ADFNode{Type: "codeBlock"}
+	if m[2] != "" {
+		node.Attrs = map[string]any{"language": m[2]}
+	}
+	if text := strings.Join(code, "\n"); text != "" {
+		node.Content = []ADFNode{{Type: "text", Text: text}}
+	}
+	return node, i + 1
+}
+
+// parseList parses a bullet or ordered list starting at lines[start].
+// Lines indented beyond the list marker belong to the current item, which
+// is how nested lists and multi-paragraph items are written.
+func parseList(lines []string, start int) (ADFNode, int) {
+	first := mdListItem.FindStringSubmatch(lines[start])
+	indent := len(first[1])
+	ordered := mdOrdered.MatchString(first[2])
+
+	list := ADFNode{Type: "bulletList"}
+	if ordered {
+		list.Type = "orderedList"
+		if order, _ := strconv.Atoi(mdOrdered.FindString(first[2])); order != 1 {
+			list.Attrs = map[string]any{"order": order}
+		}
+	}
+
+	i := start
+	for i < len(lines) {
+		m := mdListItem.FindStringSubmatch(lines[i])
+		if m == nil || len(m[1]) != indent || mdOrdered.MatchString(m[2]) != ordered {
+			break
+		}
+		width := len(m[1]) + len(m[2]) + 1
+		item := []string{m[3]}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line continues the item only if indented content follows
+				next := i + 1
+				for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+					next++
+				}
+				if next < len(lines) && leadingSpaces(lines[next]) > indent {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if sub := mdListItem.FindStringSubmatch(line); sub != nil && len(sub[1]) <= indent {
+				break
+			}
+			if leadingSpaces(line) > indent {
+				item = append(item, line[min(leadingSpaces(line), width):])
+			} else if isBlockStart(line) {
+				break
+			} else {
+				item = append(item, strings.TrimSpace(line))
+			}
+			i++
+		}
+
+		content := parseBlocks(item)
+		if len(content) == 0 || content[0].Type != "paragraph" {
+			content = append([]ADFNode{{Type: "paragraph"}}, content...)
+		}
+		list.Content = append(list.Content, ADFNode{Type: "listItem", Content: content})
+
+		// Skip blank lines between items of the same list
+		next := i
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next < len(lines) {
+			if m := mdListItem.FindStringSubmatch(lines[next]); m != nil && len(m[1]) == indent {
+				i = next
+			}
+		}
+	}
+	return list, i
+}
+
+// parseTable parses a Markdown table starting at lines[start]
+func parseTable(lines []string, start int) (ADFNode, int) {
+	table := ADFNode{Type: "table"}
+	table.Content = append(table.Content, tableRow(lines[start], "tableHeader"))
+
+	i := start + 2
+	for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+		table.Content = append(table.Content, tableRow(lines[i], "tableCell"))
+	}
+	return table, i
+}
+
+// tableRow converts a Markdown table line into a row of cells of cellType
+func tableRow(line, cellType string) ADFNode {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	row := ADFNode{Type: "tableRow"}
+	var cell strings.Builder
+	addCell := func() {
+		text := strings.TrimSpace(cell.String())
+		row.Content = append(row.Content, ADFNode{
+			Type:    cellType,
+			Content: []ADFNode{{Type: "paragraph", Content: parseInline(text, nil)}},
+		})
+		cell.Reset()
+	}
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			addCell()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	addCell()
+	return row
+}
+
+// parseParagraph converts the lines of a paragraph into inline nodes,
+// separating the lines with hard breaks
+func parseParagraph(lines []string) []ADFNode {
+	var nodes []ADFNode
+	for i, line := range lines {
+		if i > 0 {
+			nodes = append(nodes, ADFNode{Type: "hardBreak"})
+		}
+		line = strings.TrimSpace(line)
+		line = strings.TrimSuffix(line, `\`)
+		nodes = append(nodes, parseInline(line, nil)...)
+	}
+	return nodes
+}
+
+// parseInline converts inline Markdown into text nodes carrying marks
+func parseInline(text string, marks []ADFMark) []ADFNode {
+	var nodes []ADFNode
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			nodes = appendText(nodes, ADFNode{Type: "text", Text: plain.String(), Marks: marks})
+			plain.Reset()
+		}
+	}
+	withMark := func(mark ADFMark) []ADFMark {
+		return append(append([]ADFMark(nil), marks...), mark)
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_~[]()<>#|-+.!", rune(rest[1])):
+			plain.WriteByte(rest[1])
+			i += 2
+			continue
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end > 0 {
+				flush()
+				// Code can only be combined with links
+				codeMarks := []ADFMark{{Type: "code"}}
+				for _, mark := range marks {
+					if mark.Type == "link" {
+						codeMarks = append(codeMarks, mark)
+					}
+				}
+				nodes = append(nodes, ADFNode{Type: "text", Text: rest[1 : end+1], Marks: codeMarks})
+				i += end + 2
+				continue
+			}
+		case rest[0] == '[':
+			if m := mdLinkStart.FindStringSubmatch(rest); m != nil && m[1] != "" {
+				flush()
+				link := ADFMark{Type: "link", Attrs: map[string]any{"href": m[2]}}
+				nodes = appendText(nodes, parseInline(m[1], withMark(link))...)
+				i += len(m[0])
+				continue
+			}
+		case rest[0] == '<':
+			if m := mdAutolink.FindStringSubmatch(rest); m != nil {
+				flush()
+				link := ADFMark{Type: "link", Attrs: map[string]any{"href": m[1]}}
+				nodes = append(nodes, ADFNode{Type: "text", Text: m[1], Marks: withMark(link)})
+				i += len(m[0])
+				continue
+			}
+		}
+
+		if delim, markType := emphasis(text, i); delim != "" {
+			if end := closingDelimiter(text, i+len(delim), delim); end > 0 {
+				flush()
+				inner := text[i+len(delim) : end]
+				nodes = appendText(nodes, parseInline(inner, withMark(ADFMark{Type: markType}))...)
+				i = end + len(delim)
+				continue
+			}
+		}
+
+		plain.WriteByte(text[i])
+		i++
+	}
+	flush()
+	return nodes
+}
+
+// emphasis returns the emphasis delimiter starting at text[i] and its mark.
+// Underscores inside words, as in snake_case, are not emphasis.
+func emphasis(text string, i int) (string, string) {
+	rest := text[i:]
+	for _, candidate := range []struct{ delim, mark string }{
+		{"**", "strong"}, {"__", "strong"}, {"~~", "strike"}, {"*", "em"}, {"_", "em"},
+	} {
+		if !strings.HasPrefix(rest, candidate.delim) {
+			continue
+		}
+		if len(rest) == len(candidate.delim) || rest[len(candidate.delim)] == ' ' {
+			return "", ""
+		}
+		if candidate.delim[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+			return "", ""
+		}
+		return candidate.delim, candidate.mark
+	}
+	return "", ""
+}
+
+// closingDelimiter returns the index of the delimiter closing an emphasis
+// opened before from, or -1
+func closingDelimiter(text string, from int, delim string) int {
+	for i := from; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == '`' {
+			// Delimiters inside code spans do not count
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				i += end + 1
+				continue
+			}
+		}
+		if !strings.HasPrefix(text[i:], delim) || i == from || text[i-1] == ' ' {
+			continue
+		}
+		// A single delimiter must not be the start of a double one
+		if len(delim) == 1 && i+1 < len(text) && text[i+1] == delim[0] {
+			i++
+			continue
+		}
+		if delim[0] == '_' && i+1 < len(text) && isWordByte(text[i+1]) {
+			continue
+		}
+		// In a run such as "***" the nested emphasis closes first
+		run := i
+		for run < len(text) && text[run] == delim[0] {
+			run++
+		}
+		return run - len(delim)
+	}
+	return -1
+}
+
+// isWordByte reports whether b is a letter or digit
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+// appendText appends text nodes, merging neighbours that carry the same marks
+func appendText(nodes []ADFNode, more ...ADFNode) []ADFNode {
+	for _, node := range more {
+		if last := len(nodes) - 1; last >= 0 && node.Type == "text" && nodes[last].Type == "text" && sameMarks(nodes[last].Marks, node.Marks) {
+			nodes[last].Text += node.Text
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// sameMarks reports whether two mark lists are equal
+func sameMarks(a, b []ADFMark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || fmt.Sprint(a[i].Attrs) != fmt.Sprint(b[i].Attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+// leadingSpaces counts the spaces a line is indented by, a tab counting as four
+func leadingSpaces(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
@@ -0,0 +1,188 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// text returns an ADF text node with the given marks
+func text(value string, marks ...ADFMark) ADFNode {
+	return ADFNode{Type: "text", Text: value, Marks: marks}
+}
+
+// paragraph returns an ADF paragraph of the given inline nodes
+func paragraph(content ...ADFNode) ADFNode {
+	return ADFNode{Type: "paragraph", Content: content}
+}
+
+func link(href string) ADFMark {
+	return ADFMark{Type: "link", Attrs: map[string]any{"href": href}}
+}
+
+func TestADFToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		node ADFNode
+		want string
+	}{
+		{"paragraphs", ADFNode{Type: "doc", Content: []ADFNode{
+			paragraph(text("first")), paragraph(text("second")),
+		}}, "first\n\nsecond"},
+		{"marks", ADFNode{Type: "doc", Content: []ADFNode{paragraph(
+			text("bold", ADFMark{Type: "strong"}), text(" "),
+			text("italic", ADFMark{Type: "em"}), text(" "),
+			text("code", ADFMark{Type: "code"}), text(" "),
+			text("gone", ADFMark{Type: "strike"}), text(" "),
+			text("site", link("https://example.com")),
+		)}}, "**bold** *italic* `code` ~~gone~~ [site](https://example.com)"},
+		{"heading", ADFNode{Type: "doc", Content: []ADFNode{
+			{Type: "heading", Attrs: map[string]any{"level": float64(2)}, Content: []ADFNode{text("Title")}},
+		}}, "## Title"},
+		{"hard break", ADFNode{Type: "doc", Content: []ADFNode{
+			paragraph(text("one"), ADFNode{Type: "hardBreak"}, text("two")),
+		}}, "one\ntwo"},
+		{"nested lists", ADFNode{Type: "doc", Content: []ADFNode{{Type: "bulletList", Content: []ADFNode{
+			{Type: "listItem", Content: []ADFNode{
+				paragraph(text("parent")),
+				{Type: "orderedList", Content: []ADFNode{
+					{Type: "listItem", Content: []ADFNode{paragraph(text("first"))}},
+					{Type: "listItem", Content: []ADFNode{paragraph(text("second"))}},
+				}},
+			}},
+			{Type: "listItem", Content: []ADFNode{paragraph(text("sibling"))}},
+		}}}}, "- parent\n  1. first\n  2. second\n- sibling"},
+		{"code block", ADFNode{Type: "doc", Content: []ADFNode{
+			{Type: "codeBlock", Attrs: map[string]any{"language": "go"}, Content: []ADFNode{text("x := 1")}},
+		}}, "```go\nx := 1\n```"},
+		{"blockquote", ADFNode{Type: "doc", Content: []ADFNode{
+			{Type: "blockquote", Content: []ADFNode{paragraph(text("one")), paragraph(text("two"))}},
+		}}, "> one\n>\n> two"},
+		{"rule", ADFNode{Type: "doc", Content: []ADFNode{{Type: "rule"}}}, "---"},
+		{"table", ADFNode{Type: "doc", Content: []ADFNode{{Type: "table", Content: []ADFNode{
+			{Type: "tableRow", Content: []ADFNode{
+				{Type: "tableHeader", Content: []ADFNode{paragraph(text("Key"))}},
+				{Type: "tableHeader", Content: []ADFNode{paragraph(text("Status"))}},
+			}},
+			{Type: "tableRow", Content: []ADFNode{
+				{Type: "tableCell", Content: []ADFNode{paragraph(text("TEST-1"))}},
+				{Type: "tableCell", Content: []ADFNode{paragraph(text("a|b"))}},
+			}},
+		}}}}, "| Key | Status |\n| --- | --- |\n| TEST-1 | a\\|b |"},
+		{"inline nodes", ADFNode{Type: "doc", Content: []ADFNode{paragraph(
+			ADFNode{Type: "mention", Attrs: map[string]any{"id": "1", "text": "@Jane"}}, text(" "),
+			ADFNode{Type: "emoji", Attrs: map[string]any{"shortName": ":smile:", "text": "😄"}}, text(" "),
+			ADFNode{Type: "inlineCard", Attrs: map[string]any{"url": "https://example.com/TEST-1"}},
+		)}}, "@Jane 😄 https://example.com/TEST-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ADFToMarkdown(&tt.node))
+		})
+	}
+}
+
+func TestADFToText(t *testing.T) {
+	doc := &ADFNode{Type: "doc", Content: []ADFNode{
+		{Type: "heading", Attrs: map[string]any{"level": 1}, Content: []ADFNode{text("Title")}},
+		paragraph(text("bold", ADFMark{Type: "strong"}), text(" and "), text("docs", link("https://example.com"))),
+	}}
+	assert.Equal(t, "Title\n\nbold and docs (https://example.com)", ADFToText(doc))
+	assert.Empty(t, ADFToText(nil))
+}
+
+func TestMarkdownToADF(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []ADFNode
+	}{
+		{"paragraphs", "first\nline\n\nsecond", []ADFNode{
+			paragraph(text("first"), ADFNode{Type: "hardBreak"}, text("line")),
+			paragraph(text("second")),
+		}},
+		{"heading", "### Title ###", []ADFNode{
+			{Type: "heading", Attrs: map[string]any{"level": 3}, Content: []ADFNode{text("Title")}},
+		}},
+		{"emphasis", "**bold** *em* _em_ ~~strike~~ snake_case_name", []ADFNode{paragraph(
+			text("bold", ADFMark{Type: "strong"}), text(" "),
+			text("em", ADFMark{Type: "em"}), text(" "),
+			text("em", ADFMark{Type: "em"}), text(" "),
+			text("strike", ADFMark{Type: "strike"}), text(" snake_case_name"),
+		)}},
+		{"nested marks", "**bold *both***", []ADFNode{paragraph(
+			text("bold ", ADFMark{Type: "strong"}),
+			text("both", ADFMark{Type: "strong"}, ADFMark{Type: "em"}),
+		)}},
+		{"code and escapes", "`a *b*` \\*literal\\*", []ADFNode{paragraph(
+			text("a *b*", ADFMark{Type: "code"}), text(" *literal*"),
+		)}},
+		{"links", "[docs](https://example.com) <https://example.org>", []ADFNode{paragraph(
+			text("docs", link("https://example.com")), text(" "),
+			text("https://example.org", link("https://example.org")),
+		)}},
+		{"code block", "```go\nx := 1\n\ny := 2\n```", []ADFNode{
+			{Type: "codeBlock", Attrs: map[string]any{"language": "go"}, Content: []ADFNode{text("x := 1\n\ny := 2")}},
+		}},
+		{"blockquote", "> quoted\n> text", []ADFNode{
+			{Type: "blockquote", Content: []ADFNode{paragraph(text("quoted"), ADFNode{Type: "hardBreak"}, text("text"))}},
+		}},
+		{"rule", "---", []ADFNode{{Type: "rule"}}},
+		{"nested list", "- parent\n  1. child\n- sibling", []ADFNode{{Type: "bulletList", Content: []ADFNode{
+			{Type: "listItem", Content: []ADFNode{
+				paragraph(text("parent")),
+				{Type: "orderedList", Content: []ADFNode{
+					{Type: "listItem", Content: []ADFNode{paragraph(text("child"))}},
+				}},
+			}},
+			{Type: "listItem", Content: []ADFNode{paragraph(text("sibling"))}},
+		}}}},
+		{"ordered list start", "3. third\n4. fourth", []ADFNode{{Type: "orderedList", Attrs: map[string]any{"order": 3}, Content: []ADFNode{
+			{Type: "listItem", Content: []ADFNode{paragraph(text("third"))}},
+			{Type: "listItem", Content: []ADFNode{paragraph(text("fourth"))}},
+		}}}},
+		{"table", "| A | B |\n|---|---|\n| 1 | 2 |", []ADFNode{{Type: "table", Content: []ADFNode{
+			{Type: "tableRow", Content: []ADFNode{
+				{Type: "tableHeader", Content: []ADFNode{paragraph(text("A"))}},
+				{Type: "tableHeader", Content: []ADFNode{paragraph(text("B"))}},
+			}},
+			{Type: "tableRow", Content: []ADFNode{
+				{Type: "tableCell", Content: []ADFNode{paragraph(text("1"))}},
+				{Type: "tableCell", Content: []ADFNode{paragraph(text("2"))}},
+			}},
+		}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := MarkdownToADF(tt.markdown)
+			assert.Equal(t, "doc", doc.Type)
+			assert.Equal(t, 1, doc.Version)
+			assert.Equal(t, tt.want, doc.Content)
+		})
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	markdown := "## Steps\n\n" +
+		"1. Open **the** page\n2. Click [save](https://example.com/save)\n   - twice\n\n" +
+		"> Note: `config.yaml` is *required*\n\n" +
+		"```sh\nowlify issue -k TEST-1\n```\n\n" +
+		"| Key | Status |\n| --- | --- |\n| TEST-1 | Done |\n\n" +
+		"---\n\n" +
+		"Thanks ~~all~~"
+	assert.Equal(t, markdown, ADFToMarkdown(MarkdownToADF(markdown)))
+}
+
+func TestADFDocumentJSON(t *testing.T) {
+	data, err := json.Marshal(MarkdownToADF(""))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"doc","version":1,"content":[]}`, string(data))
+
+	data, err = json.Marshal(MarkdownToADF("**hi**"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"doc","version":1,"content":[
+		{"type":"paragraph","content":[{"type":"text","text":"hi","marks":[{"type":"strong"}]}]}
+	]}`, string(data))
+}
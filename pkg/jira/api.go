@@ -0,0 +1,70 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+// APIVersion is a version of the Jira platform REST API
+type APIVersion string
+
+const (
+	// APIVersion2 is supported by Jira Server, Data Center and Cloud.
+	// Descriptions and comments are strings in Jira wiki markup.
+	APIVersion2 APIVersion = "2"
+	// APIVersion3 is only available on Jira Cloud. Descriptions and
+	// comments are Atlassian Document Format (ADF) documents.
+	APIVersion3 APIVersion = "3"
+)
+
+// DefaultAPIVersion is the REST API version used unless configured otherwise
+const DefaultAPIVersion = APIVersion2
+
+// ParseAPIVersion parses a REST API version such as "2", "3" or "v3"
+func ParseAPIVersion(value string) (APIVersion, error) {
+	switch v := APIVersion(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "v")); v {
+	case "":
+		return DefaultAPIVersion, nil
+	case APIVersion2, APIVersion3:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unsupported Jira API version %q: expected 2 or 3", value)
+	}
+}
+
+// WithAPIVersion sets the platform REST API version the client talks to.
+// The Agile API is not affected, it only exists as version 1.0.
+func WithAPIVersion(version APIVersion) ClientOption {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// APIVersion returns the platform REST API version the client talks to
+func (c *Client) APIVersion() APIVersion {
+	return c.apiVersion
+}
+
+// apiURL returns the URL of a platform REST API resource for the client's
+// API version. The path is formatted with args like fmt.Sprintf.
+func (c *Client) apiURL(format string, args ...any) string {
+	return fmt.Sprintf("%s/rest/api/%s/%s", c.baseURL, c.apiVersion, fmt.Sprintf(format, args...))
+}
+
+// richText converts Markdown into the representation Jira expects for
+// descriptions in the client's API version. Comments go through commentText.
+func (c *Client) richText(markdown string) any {
+	if c.apiVersion == APIVersion3 {
+		return MarkdownToADF(markdown)
+	}
+	return markdown
+}
+
+// commentText converts the Markdown of a comment, worklog comment or
+// transition comment into an ADF document on v3 and wiki markup on v2
+func (c *Client) commentText(markdown string) any {
+	if c.apiVersion == APIVersion3 {
+		return MarkdownToADF(markdown)
+	}
+	return MarkdownToWiki(markdown)
+}
@@ -0,0 +1,133 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    APIVersion
+		wantErr bool
+	}{
+		{"", APIVersion2, false},
+		{"2", APIVersion2, false},
+		{"3", APIVersion3, false},
+		{"v3", APIVersion3, false},
+		{"latest", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseAPIVersion(tt.value)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unsupported Jira API version")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAPIURL(t *testing.T) {
+	client := NewClient(testBaseURL, testAuth)
+	assert.Equal(t, APIVersion2, client.APIVersion())
+	assert.Equal(t, testBaseURL+"/rest/api/2/issue/TEST-1", client.apiURL("issue/%s", "TEST-1"))
+
+	client = NewClient(testBaseURL, testAuth, WithAPIVersion(APIVersion3))
+	assert.Equal(t, testBaseURL+"/rest/api/3/search?jql=x", client.apiURL("search?jql=%s", "x"))
+}
+
+func TestAPIVersionSelectsEndpoints(t *testing.T) {
+	var requested string
+	client := NewClient(testBaseURL, testAuth, WithAPIVersion(APIVersion3),
+		WithRequestFunc(func(ctx context.Context, url string, target any) error {
+			requested = url
+			return nil
+		}))
+
+	_, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", 0)
+	require.NoError(t, err)
+	assert.Equal(t, testBaseURL+"/rest/api/3/search?jql=project%3DTEST", requested)
+
+	_, err = client.GetIssue(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	assert.Equal(t, testBaseURL+"/rest/api/3/issue/TEST-1", requested)
+}
+
+func TestRichTextUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want RichText
+	}{
+		{"null", `null`, ""},
+		{"wiki markup", `"h1. Title"`, "h1. Title"},
+		{"adf", `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[
+			{"type":"text","text":"Hello","marks":[{"type":"strong"}]}]}]}`, "**Hello**"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got RichText
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var got RichText
+	assert.Error(t, json.Unmarshal([]byte(`[1]`), &got))
+}
+
+func TestIssueDescriptionV3(t *testing.T) {
+	var stored json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/3/issue/TEST-1", r.URL.Path)
+		switch r.Method {
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var payload struct {
+				Fields struct {
+					Description json.RawMessage `json:"description"`
+				} `json:"fields"`
+			}
+			require.NoError(t, json.Unmarshal(body, &payload))
+			stored = payload.Fields.Description
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			assert.Equal(t, "description", r.URL.Query().Get("fields"))
+			_, _ = w.Write([]byte(`{"key":"TEST-1","fields":{"description":` + string(stored) + `}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), WithAPIVersion(APIVersion3))
+	markdown := "Fix the *login* page\n\n- step one\n- step two"
+	require.NoError(t, client.SetIssueDescription(context.Background(), "TEST-1", markdown))
+	assert.Contains(t, string(stored), `"type":"doc"`)
+
+	description, err := client.GetIssueDescription(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	assert.Equal(t, RichText(markdown), description)
+}
+
+func TestSetIssueDescriptionV2(t *testing.T) {
+	var payload any
+	client := NewClient(testBaseURL, testAuth,
+		WithPutRequestFunc(func(ctx context.Context, url string, body any, target any) error {
+			assert.Equal(t, testBaseURL+"/rest/api/2/issue/TEST-1", url)
+			payload = body
+			return nil
+		}))
+
+	require.NoError(t, client.SetIssueDescription(context.Background(), "TEST-1", "plain"))
+	assert.Equal(t, map[string]any{"fields": map[string]any{"description": "plain"}}, payload)
+}
@@ -0,0 +1,60 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Authenticator adds credentials to outgoing Jira requests
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// BearerAuth authenticates with a personal access token (Jira Server/Data Center)
+type BearerAuth struct {
+	Token string
+}
+
+// Authenticate implements Authenticator
+func (a BearerAuth) Authenticate(req *http.Request) error {
+	if a.Token == "" {
+		return fmt.Errorf("bearer authentication requires a token")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.Token))
+	return nil
+}
+
+// BasicAuth authenticates with a username and token. On Jira Cloud the
+// username is the account email and the token is an Atlassian API token.
+type BasicAuth struct {
+	Username string
+	Token    string
+}
+
+// Authenticate implements Authenticator
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	if a.Username == "" || a.Token == "" {
+		return fmt.Errorf("basic authentication requires a username and a token")
+	}
+	req.SetBasicAuth(a.Username, a.Token)
+	return nil
+}
+
+// SessionCookieAuth authenticates with an existing session cookie, such as
+// a JSESSIONID taken from a browser logged in through SSO.
+type SessionCookieAuth struct {
+	// Cookie holds one or more cookies in "name=value; name2=value2" form
+	Cookie string
+}
+
+// Authenticate implements Authenticator
+func (a SessionCookieAuth) Authenticate(req *http.Request) error {
+	cookies, err := http.ParseCookie(a.Cookie)
+	if err != nil {
+		return fmt.Errorf("invalid session cookie: %w", err)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	return nil
+}
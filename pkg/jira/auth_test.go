@@ -0,0 +1,94 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticators(t *testing.T) {
+	tests := []struct {
+		name           string
+		auth           Authenticator
+		expectedHeader string
+		expectedCookie string
+		expectedError  bool
+	}{
+		{
+			name:           "bearer token",
+			auth:           BearerAuth{Token: "pat"},
+			expectedHeader: "Bearer pat",
+		},
+		{
+			name:          "bearer without token",
+			auth:          BearerAuth{},
+			expectedError: true,
+		},
+		{
+			name:           "basic with api token",
+			auth:           BasicAuth{Username: "me@example.com", Token: "api-token"},
+			expectedHeader: "Basic " + base64.StdEncoding.EncodeToString([]byte("me@example.com:api-token")),
+		},
+		{
+			name:          "basic without username",
+			auth:          BasicAuth{Token: "api-token"},
+			expectedError: true,
+		},
+		{
+			name:           "session cookie",
+			auth:           SessionCookieAuth{Cookie: "JSESSIONID=abc123; atlassian.xsrf.token=xyz"},
+			expectedCookie: "JSESSIONID=abc123; atlassian.xsrf.token=xyz",
+		},
+		{
+			name:          "empty session cookie",
+			auth:          SessionCookieAuth{},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://jira.example.com/rest/api/2/myself", nil)
+			err := tt.auth.Authenticate(req)
+
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHeader, req.Header.Get("Authorization"))
+			assert.Equal(t, tt.expectedCookie, req.Header.Get("Cookie"))
+		})
+	}
+}
+
+func TestClientAnonymousRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil, WithHTTPClient(server.Client()))
+	_, err := client.FetchBoardByID(context.Background(), 1)
+	assert.NoError(t, err)
+}
+
+func TestClientBasicAuthRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, token, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "me@example.com", username)
+		assert.Equal(t, "api-token", token)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, BasicAuth{Username: "me@example.com", Token: "api-token"}, WithHTTPClient(server.Client()))
+	_, err := client.FetchBoardByID(context.Background(), 1)
+	assert.NoError(t, err)
+}
@@ -1,28 +1,40 @@
 package jira
 
 import (
+	"context"
 	"fmt"
+	"iter"
 )
 
 // FetchBoards retrieves all Jira boards associated with the specified project.
-// It makes a GET request to the Jira API and returns the list of boards.
+// It follows pagination until every board has been fetched or limit is reached.
 //
 // Parameters:
 //   - project: The project key or ID to fetch boards for
-//   - makeGetRequest: Function to make the Jira API request
+//   - limit: Maximum number of boards to return (zero or less for all)
 //
 // Returns:
 //   - []Board: Slice of Board objects if successful
 //   - error: Error if the request fails
-func FetchBoards(project string, makeGetRequest JiraRequestFunc) ([]Board, error) {
-	var boardResp BoardResponse
-	// JQL to find boards for the project and component
-	boardSearchURL := fmt.Sprintf("%s/rest/agile/1.0/board?projectKeyOrId=%s", jiraBaseURL, project)
+func (c *Client) FetchBoards(ctx context.Context, project string, limit int) ([]Board, error) {
+	return collect(c.Boards(ctx, project), limit)
+}
 
-	if err := makeGetRequest(boardSearchURL, &boardResp); err != nil {
-		return nil, err
-	}
-	return boardResp.Values, nil
+// Boards returns an iterator over all boards of the specified project.
+// Pages are requested from Jira as the iterator advances.
+func (c *Client) Boards(ctx context.Context, project string) iter.Seq2[Board, error] {
+	return paginate(0, func(startAt int) ([]Board, pageInfo, error) {
+		var boardResp BoardResponse
+		boardSearchURL := fmt.Sprintf("%s/rest/agile/1.0/board?projectKeyOrId=%s", c.baseURL, project)
+		if startAt > 0 {
+			boardSearchURL = fmt.Sprintf("%s&startAt=%d", boardSearchURL, startAt)
+		}
+
+		if err := c.makeGetRequest(ctx, boardSearchURL, &boardResp); err != nil {
+			return nil, pageInfo{}, err
+		}
+		return boardResp.Values, boardResp.pageInfo(), nil
+	})
 }
 
 // FetchBoardByName retrieves a Jira board with the specified name.
@@ -30,17 +42,16 @@ func FetchBoards(project string, makeGetRequest JiraRequestFunc) ([]Board, error
 //
 // Parameters:
 //   - name: The name of the board to fetch
-//   - makeGetRequest: Function to make the Jira API request
 //
 // Returns:
 //   - Board: Board object if successful
 //   - error: Error if the request fails
-func FetchBoardByName(name string, makeGetRequest JiraRequestFunc) (Board, error) {
+func (c *Client) FetchBoardByName(ctx context.Context, name string) (Board, error) {
 	var boardResp BoardResponse
 	// JQL to find boards for the project and component
-	boardSearchURL := fmt.Sprintf("%s/rest/agile/1.0/board?name=%s", jiraBaseURL, name)
+	boardSearchURL := fmt.Sprintf("%s/rest/agile/1.0/board?name=%s", c.baseURL, name)
 
-	if err := makeGetRequest(boardSearchURL, &boardResp); err != nil {
+	if err := c.makeGetRequest(ctx, boardSearchURL, &boardResp); err != nil {
 		return Board{}, err
 	}
 	if len(boardResp.Values) == 0 {
@@ -54,17 +65,16 @@ func FetchBoardByName(name string, makeGetRequest JiraRequestFunc) (Board, error
 //
 // Parameters:
 //   - id: The ID of the board to fetch
-//   - makeGetRequest: Function to make the Jira API request
 //
 // Returns:
 //   - Board: Board object if successful
 //   - error: Error if the request fails
-func FetchBoardByID(id int, makeGetRequest JiraRequestFunc) (Board, error) {
+func (c *Client) FetchBoardByID(ctx context.Context, id int) (Board, error) {
 	var boardResp Board
 	// JQL to find boards for the project and component
-	boardSearchURL := fmt.Sprintf("%s/rest/agile/1.0/board/%d", jiraBaseURL, id)
+	boardSearchURL := fmt.Sprintf("%s/rest/agile/1.0/board/%d", c.baseURL, id)
 
-	if err := makeGetRequest(boardSearchURL, &boardResp); err != nil {
+	if err := c.makeGetRequest(ctx, boardSearchURL, &boardResp); err != nil {
 		return Board{}, err
 	}
 	return boardResp, nil
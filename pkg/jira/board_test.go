@@ -1,6 +1,7 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -51,13 +52,13 @@ func TestFetchBoards(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock request function
-			mockRequest := func(url string, target any) error {
+			mockRequest := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
 
 				// Check URL format
-				expectedURL := fmt.Sprintf("%s/rest/agile/1.0/board?projectKeyOrId=%s", jiraBaseURL, tt.project)
+				expectedURL := fmt.Sprintf("%s/rest/agile/1.0/board?projectKeyOrId=%s", testBaseURL, tt.project)
 				if url != expectedURL {
 					t.Errorf("incorrect URL: got %s, want %s", url, expectedURL)
 				}
@@ -68,7 +69,7 @@ func TestFetchBoards(t *testing.T) {
 			}
 
 			// Call the function
-			boards, err := FetchBoards(tt.project, mockRequest)
+			boards, err := newTestClient(mockRequest).FetchBoards(context.Background(), tt.project, 0)
 
 			// Check error
 			if (err != nil) != tt.expectedError {
@@ -128,7 +129,7 @@ func TestFetchBoardByName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockGet := func(url string, target any) error {
+			mockGet := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
@@ -139,7 +140,7 @@ func TestFetchBoardByName(t *testing.T) {
 				return nil
 			}
 
-			board, err := FetchBoardByName(tt.boardName, mockGet)
+			board, err := newTestClient(mockGet).FetchBoardByName(context.Background(), tt.boardName)
 
 			// Check error cases
 			if tt.expectedErrMsg != "" {
@@ -197,7 +198,7 @@ func TestFetchBoardByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock request function
-			mockRequest := func(url string, target any) error {
+			mockRequest := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
@@ -208,7 +209,7 @@ func TestFetchBoardByID(t *testing.T) {
 			}
 
 			// Call the function being tested
-			board, err := FetchBoardByID(tt.id, mockRequest)
+			board, err := newTestClient(mockRequest).FetchBoardByID(context.Background(), tt.id)
 
 			// Check error
 			if tt.mockError != nil {
@@ -0,0 +1,338 @@
+package jira
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultCacheTTLs returns how long responses of each endpoint are cached.
+// Keys are path prefixes relative to the Jira base URL, where "*" matches a
+// single path segment; the longest matching prefix wins, and among prefixes
+// of the same length the one with fewer wildcards. Endpoints without
+// a match, or with a TTL of zero, are never cached.
+func DefaultCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		"/rest/api/*/search":              5 * time.Minute,
+		"/rest/api/*/issue":               time.Minute,
+		"/rest/api/*/issue/*/transitions": 0,
+		"/rest/api/*/serverInfo":          24 * time.Hour,
+		"/rest/api/*/field":               24 * time.Hour,
+		"/rest/agile/1.0/board":           time.Hour,
+		"/rest/agile/1.0/board/*/sprint":  5 * time.Minute,
+		"/rest/agile/1.0/sprint":          5 * time.Minute,
+		"/rest/agile/1.0/sprint/*/issue":  5 * time.Minute,
+	}
+}
+
+// Cache stores GET responses on disk so repeated reports do not hit Jira
+// again. Entries are keyed by method, URL and namespace (the configuration
+// profile), expire after a per-endpoint TTL and are then revalidated with
+// ETag/Last-Modified when Jira provided them.
+//
+// Jira marks most REST responses as no-store; the cache deliberately
+// ignores Cache-Control because it is an explicit opt-in of the user.
+type Cache struct {
+	dir       string
+	namespace string
+	ttls      map[string]time.Duration
+	refresh   bool
+	now       func() time.Time
+}
+
+// CacheOption is a function that modifies a Cache
+type CacheOption func(*Cache)
+
+// NewCache creates a cache storing entries below dir for the given namespace
+func NewCache(dir, namespace string, options ...CacheOption) *Cache {
+	c := &Cache{
+		dir:       dir,
+		namespace: namespace,
+		ttls:      DefaultCacheTTLs(),
+		now:       time.Now,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// WithCacheTTLs overrides the TTLs of the given endpoints, keeping the
+// defaults of all others
+func WithCacheTTLs(ttls map[string]time.Duration) CacheOption {
+	return func(c *Cache) {
+		for prefix, ttl := range ttls {
+			c.ttls[prefix] = ttl
+		}
+	}
+}
+
+// WithCacheRefresh ignores cached entries and always fetches from Jira,
+// storing the fresh responses for later runs
+func WithCacheRefresh(refresh bool) CacheOption {
+	return func(c *Cache) {
+		c.refresh = refresh
+	}
+}
+
+// WithCache enables caching of GET responses
+func WithCache(cache *Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// cacheEntry is a cached response as stored on disk
+type cacheEntry struct {
+	Method       string    `json:"method"`
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Body         []byte    `json:"body"`
+	StoredAt     time.Time `json:"stored_at"`
+	Expires      time.Time `json:"expires"`
+}
+
+// fresh reports whether the entry can be used without asking Jira
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// setValidators makes req conditional on the entry being unchanged
+func (e *cacheEntry) setValidators(req *http.Request) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// ttl returns how long responses of endpoint are cached
+func (c *Cache) ttl(endpoint string) time.Duration {
+	bestSegments, bestLiterals, ttl := -1, -1, time.Duration(0)
+	for prefix, prefixTTL := range c.ttls {
+		segments, literals := matchEndpoint(prefix, endpoint)
+		if segments > bestSegments || segments == bestSegments && literals > bestLiterals {
+			bestSegments, bestLiterals, ttl = segments, literals, prefixTTL
+		}
+	}
+	return ttl
+}
+
+// matchEndpoint returns the number of segments of prefix, and how many of
+// them are not wildcards, when it matches the start of endpoint, or -1 when
+// it does not
+func matchEndpoint(prefix, endpoint string) (int, int) {
+	want := strings.Split(strings.Trim(prefix, "/"), "/")
+	have := strings.Split(strings.Trim(endpoint, "/"), "/")
+	if len(want) > len(have) {
+		return -1, -1
+	}
+	literals := 0
+	for i, segment := range want {
+		if segment == "*" {
+			continue
+		}
+		if segment != have[i] {
+			return -1, -1
+		}
+		literals++
+	}
+	return len(want), literals
+}
+
+// namespaceDir is the directory holding the entries of the cache's namespace
+func (c *Cache) namespaceDir() string {
+	return filepath.Join(c.dir, url.PathEscape(c.namespace))
+}
+
+// path returns the file the entry for req is stored in
+func (c *Cache) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String() + " " + c.namespace))
+	return filepath.Join(c.namespaceDir(), hex.EncodeToString(sum[:])+".json")
+}
+
+// lookup returns the stored entry for req. Unreadable entries are treated
+// as missing so a corrupt cache never breaks a command.
+func (c *Cache) lookup(req *http.Request) *cacheEntry {
+	if c.refresh {
+		return nil
+	}
+	data, err := os.ReadFile(c.path(req))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store saves a successful response body for req
+func (c *Cache) store(req *http.Request, resp *http.Response, body []byte, ttl time.Duration) error {
+	now := c.now()
+	return c.write(req, &cacheEntry{
+		Method:       req.Method,
+		URL:          req.URL.Redacted(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Body:         body,
+		StoredAt:     now,
+		Expires:      now.Add(ttl),
+	})
+}
+
+// revalidated extends the lifetime of an entry Jira reported as unchanged
+func (c *Cache) revalidated(req *http.Request, entry *cacheEntry, ttl time.Duration) error {
+	now := c.now()
+	entry.StoredAt = now
+	entry.Expires = now.Add(ttl)
+	return c.write(req, entry)
+}
+
+// write atomically replaces the entry for req
+func (c *Cache) write(req *http.Request, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.namespaceDir(), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.namespaceDir(), "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(req))
+}
+
+// invalidate drops every entry of the namespace. It is called after Jira
+// accepted a change, since any cached search may include the changed issue.
+func (c *Cache) invalidate() error {
+	return os.RemoveAll(c.namespaceDir())
+}
+
+// CacheStats summarises the contents of a cache directory
+type CacheStats struct {
+	Entries int
+	Fresh   int
+	Expired int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// Stats summarises the entries of every namespace in the cache directory
+func (c *Cache) Stats() (CacheStats, error) {
+	var stats CacheStats
+	now := c.now()
+	err := c.walkEntries(func(path string, info fs.FileInfo) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			// Corrupt entries still take up space
+			stats.Entries++
+			stats.Expired++
+			stats.Bytes += info.Size()
+			return nil
+		}
+
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if entry.fresh(now) {
+			stats.Fresh++
+		} else {
+			stats.Expired++
+		}
+		if stats.Oldest.IsZero() || entry.StoredAt.Before(stats.Oldest) {
+			stats.Oldest = entry.StoredAt
+		}
+		if entry.StoredAt.After(stats.Newest) {
+			stats.Newest = entry.StoredAt
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Clear removes the entries of every namespace and returns how many were removed
+func (c *Cache) Clear() (int, error) {
+	removed := 0
+	err := c.walkEntries(func(path string, _ fs.FileInfo) error {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// walkEntries calls fn for every entry file in the cache directory
+func (c *Cache) walkEntries(fn func(path string, info fs.FileInfo) error) error {
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, info)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading cache %s: %w", c.dir, err)
+	}
+	return nil
+}
+
+// ParseCacheTTLs parses endpoint TTLs in "prefix=duration,prefix=duration"
+// form, e.g. "/rest/api/*/search=10m,/rest/agile/1.0/board=0"
+func ParseCacheTTLs(value string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		prefix, duration, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cache TTL %q: expected prefix=duration", item)
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache TTL %q: %w", item, err)
+		}
+		ttls[strings.TrimSpace(prefix)] = ttl
+	}
+	return ttls, nil
+}
@@ -0,0 +1,287 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCachedTestClient creates a client for server with a cache in a temporary directory
+func newCachedTestClient(t *testing.T, server *httptest.Server, clock *fakeClock, options ...CacheOption) (*Client, *Cache) {
+	t.Helper()
+	cache := NewCache(t.TempDir(), "default", options...)
+	cache.now = clock.Now
+	return NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), WithCache(cache), testServerInfo, testFieldIDs), cache
+}
+
+func TestCacheServesFreshResponses(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(JiraResponse{Issues: []Issue{{Key: "TEST-1"}}})
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, _ := newCachedTestClient(t, server, clock)
+
+	for i := 0; i < 3; i++ {
+		issues, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "TEST-1", issues[0].Key)
+	}
+	assert.Equal(t, 1, requests)
+
+	// Once the TTL has passed the response is fetched again
+	clock.Advance(6 * time.Minute)
+	_, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(Board{ID: 42, Name: "Board"})
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, _ := newCachedTestClient(t, server, clock)
+
+	_, err := client.FetchBoardByID(context.Background(), 42)
+	require.NoError(t, err)
+
+	clock.Advance(2 * time.Hour)
+	board, err := client.FetchBoardByID(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, "Board", board.Name)
+	assert.Equal(t, 2, requests)
+
+	// The revalidated entry is fresh again
+	_, err = client.FetchBoardByID(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCacheRevalidatesWithLastModified(t *testing.T) {
+	const lastModified = "Mon, 02 Jan 2006 15:04:05 GMT"
+	var conditional string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = r.Header.Get("If-Modified-Since")
+		w.Header().Set("Last-Modified", lastModified)
+		_ = json.NewEncoder(w).Encode(Issue{Key: "TEST-1"})
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, _ := newCachedTestClient(t, server, clock)
+
+	_, err := client.GetIssue(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	assert.Empty(t, conditional)
+
+	clock.Advance(2 * time.Minute)
+	_, err = client.GetIssue(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	assert.Equal(t, lastModified, conditional)
+}
+
+func TestCacheRefresh(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode(Issue{Key: "TEST-1"})
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, cache := newCachedTestClient(t, server, clock, WithCacheRefresh(true))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetIssue(context.Background(), "TEST-1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, requests)
+
+	// Refreshed responses are still stored for later runs
+	stats, err := cache.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestCacheSkipsUncachedEndpoints(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(TransitionResponse{})
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, cache := newCachedTestClient(t, server, clock)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetAvailableTransitions(context.Background(), Issue{Key: "TEST-1"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, requests)
+
+	stats, err := cache.Stats()
+	require.NoError(t, err)
+	assert.Zero(t, stats.Entries)
+}
+
+func TestCacheInvalidatedByWrites(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			requests++
+			_ = json.NewEncoder(w).Encode(Issue{Key: "TEST-1"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, _ := newCachedTestClient(t, server, clock)
+
+	_, err := client.GetIssue(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	require.NoError(t, client.UpdateIssueStatus(context.Background(), "TEST-1", "31"))
+	_, err = client.GetIssue(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Now()}
+	client, _ := newCachedTestClient(t, server, clock)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetIssue(context.Background(), "TEST-1")
+		assert.True(t, IsNotFound(err))
+	}
+	assert.Equal(t, 2, requests)
+}
+
+func TestCacheKeyedByNamespace(t *testing.T) {
+	dir := t.TempDir()
+	req := httptest.NewRequest("GET", testBaseURL+"/rest/api/2/issue/TEST-1", nil)
+
+	first := NewCache(dir, "work")
+	second := NewCache(dir, "personal")
+	assert.NotEqual(t, first.path(req), second.path(req))
+}
+
+func TestCacheTTL(t *testing.T) {
+	cache := NewCache(t.TempDir(), "default", WithCacheTTLs(map[string]time.Duration{
+		"/rest/api/2/search": 10 * time.Minute,
+	}))
+
+	// A literal version segment beats the default wildcard of the same length
+
+	tests := []struct {
+		endpoint string
+		want     time.Duration
+	}{
+		{"/rest/api/2/search", 10 * time.Minute},
+		{"/rest/api/3/search", 5 * time.Minute},
+		{"/rest/api/2/issue/TEST-1", time.Minute},
+		{"/rest/api/3/issue/TEST-1/transitions", 0},
+		{"/rest/api/2/issue/TEST-1/transitions", 0},
+		{"/rest/agile/1.0/board/7", time.Hour},
+		{"/rest/agile/1.0/board/7/sprint", 5 * time.Minute},
+		{"/rest/api/2/myself", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			assert.Equal(t, tt.want, cache.ttl(tt.endpoint))
+		})
+	}
+}
+
+func TestClientEndpoint(t *testing.T) {
+	client := NewClient(AtlassianAPIURL+"/cloud-id", testAuth)
+	req := httptest.NewRequest("GET", AtlassianAPIURL+"/cloud-id/rest/api/2/search?jql=x", nil)
+	assert.Equal(t, "/rest/api/2/search", client.endpoint(req.URL))
+}
+
+func TestCacheStatsAndClear(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Now()}
+	cache := NewCache(dir, "default")
+	cache.now = clock.Now
+
+	resp := &http.Response{Header: http.Header{}}
+	fresh := httptest.NewRequest("GET", testBaseURL+"/rest/api/2/issue/TEST-1", nil)
+	expired := httptest.NewRequest("GET", testBaseURL+"/rest/api/2/issue/TEST-2", nil)
+	require.NoError(t, cache.store(expired, resp, []byte(`{}`), time.Minute))
+	clock.Advance(2 * time.Minute)
+	require.NoError(t, cache.store(fresh, resp, []byte(`{}`), time.Minute))
+
+	info, err := os.Stat(filepath.Dir(cache.path(fresh)))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	stats, err := cache.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 1, stats.Fresh)
+	assert.Equal(t, 1, stats.Expired)
+	assert.Positive(t, stats.Bytes)
+	assert.True(t, stats.Oldest.Before(stats.Newest))
+
+	removed, err := NewCache(dir, "other").Clear()
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	stats, err = cache.Stats()
+	require.NoError(t, err)
+	assert.Zero(t, stats.Entries)
+}
+
+func TestCacheStatsMissingDir(t *testing.T) {
+	stats, err := NewCache(filepath.Join(t.TempDir(), "missing"), "default").Stats()
+	require.NoError(t, err)
+	assert.Zero(t, stats.Entries)
+}
+
+func TestParseCacheTTLs(t *testing.T) {
+	ttls, err := ParseCacheTTLs("/rest/api/*/search=10m, /rest/agile/1.0/board=0")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"/rest/api/*/search":    10 * time.Minute,
+		"/rest/agile/1.0/board": 0,
+	}, ttls)
+
+	_, err = ParseCacheTTLs("/rest/api/2/search")
+	assert.ErrorContains(t, err, "expected prefix=duration")
+
+	_, err = ParseCacheTTLs("/rest/api/2/search=soon")
+	assert.ErrorContains(t, err, "invalid cache TTL")
+}
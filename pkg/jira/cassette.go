@@ -0,0 +1,325 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotRecorded is returned when replaying a request that is not in the cassette
+var ErrNotRecorded = errors.New("no recorded response")
+
+// redacted replaces secrets in recorded interactions
+const redacted = "REDACTED"
+
+// sensitiveHeaders are never written to a cassette
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// sensitiveParams are query, form and JSON keys whose values are redacted
+var sensitiveParams = map[string]bool{
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"client_secret": true,
+	"code":          true,
+	"jwt":           true,
+	"api_key":       true,
+	"password":      true,
+}
+
+// Interaction is a recorded request/response pair as stored in a cassette
+type Interaction struct {
+	Request    RecordedRequest  `json:"request"`
+	Response   RecordedResponse `json:"response"`
+	RecordedAt time.Time        `json:"recorded_at"`
+}
+
+// RecordedRequest is the sanitized request of an Interaction
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the sanitized response of an Interaction
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that stores every request it sends
+// and the response received as numbered files in a cassette directory.
+// Credentials are redacted before anything is written.
+type Recorder struct {
+	dir       string
+	transport http.RoundTripper
+
+	mu  sync.Mutex
+	seq int
+}
+
+// NewRecorder creates a Recorder writing to dir that sends requests with
+// transport. Recording into an existing cassette appends to it.
+func NewRecorder(dir string, transport http.RoundTripper) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating cassette directory: %w", err)
+	}
+	files, err := cassetteFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{dir: dir, transport: transport, seq: len(files)}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		reqBody, err = io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: redactHeader(req.Header),
+			Body:   redactBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       redactBody(respBody),
+		},
+		RecordedAt: time.Now().UTC(),
+	}
+	if err := r.save(req, interaction); err != nil {
+		return nil, fmt.Errorf("error recording interaction: %w", err)
+	}
+	return resp, nil
+}
+
+// save writes interaction to the next numbered cassette file
+func (r *Recorder) save(req *http.Request, interaction Interaction) error {
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	name := fmt.Sprintf("%04d-%s%s.json", r.seq, strings.ToLower(req.Method), slug(req.URL.Path))
+	return os.WriteFile(filepath.Join(r.dir, name), data, 0600)
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette
+// directory instead of the network. Requests are matched by method, path
+// and query, ignoring the base URL so cassettes work with any instance.
+// Repeated requests get the recorded responses in order, the last one
+// being reused once they run out.
+type Replayer struct {
+	dir string
+
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+}
+
+// NewReplayer loads the cassette in dir
+func NewReplayer(dir string) (*Replayer, error) {
+	files, err := cassetteFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("cassette %s contains no recorded interactions", dir)
+	}
+
+	r := &Replayer{dir: dir, interactions: make(map[string][]Interaction)}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("error reading cassette file %s: %w", file, err)
+		}
+		recordedURL, err := url.Parse(interaction.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL in cassette file %s: %w", file, err)
+		}
+		key := interactionKey(interaction.Request.Method, recordedURL)
+		r.interactions[key] = append(r.interactions[key], interaction)
+	}
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	key := interactionKey(req.Method, req.URL)
+	r.mu.Lock()
+	queue := r.interactions[key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w for %s %s in cassette %s", ErrNotRecorded, req.Method, redactURL(req.URL), r.dir)
+	}
+	interaction := queue[0]
+	if len(queue) > 1 {
+		r.interactions[key] = queue[1:]
+	}
+	r.mu.Unlock()
+
+	body := interaction.Response.Body
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// interactionKey identifies requests that are answered by the same recordings.
+// Anything before /rest/, such as a context path or the OAuth gateway
+// prefix, is ignored along with the host.
+func interactionKey(method string, u *url.URL) string {
+	path := u.Path
+	if i := strings.Index(path, "/rest/"); i > 0 {
+		path = path[i:]
+	}
+	return fmt.Sprintf("%s %s?%s", method, path, redactValues(u.Query()).Encode())
+}
+
+// cassetteFiles lists the interaction files of a cassette in recording order
+func cassetteFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("error opening cassette: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// redactURL returns u as a string with credentials and sensitive query values removed
+func redactURL(u *url.URL) string {
+	clean := *u
+	if clean.User != nil {
+		clean.User = url.User(redacted)
+	}
+	clean.RawQuery = redactValues(clean.Query()).Encode()
+	return clean.String()
+}
+
+// redactValues masks sensitive query or form values in place
+func redactValues(values url.Values) url.Values {
+	for key := range values {
+		if sensitiveParams[strings.ToLower(key)] {
+			values.Set(key, redacted)
+		}
+	}
+	return values
+}
+
+// redactHeader returns a copy of header without credentials
+func redactHeader(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if clean.Get(name) != "" {
+			clean.Set(name, redacted)
+		}
+	}
+	if len(clean) == 0 {
+		return nil
+	}
+	return clean
+}
+
+// redactBody masks sensitive values in JSON and form-encoded bodies
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var object map[string]any
+	if json.Unmarshal(body, &object) == nil {
+		changed := false
+		for key := range object {
+			if sensitiveParams[strings.ToLower(key)] {
+				object[key] = redacted
+				changed = true
+			}
+		}
+		if !changed {
+			return string(body)
+		}
+		if data, err := json.Marshal(object); err == nil {
+			return string(data)
+		}
+	}
+
+	// Form-encoded bodies, as sent to the OAuth token endpoint
+	if form, err := url.ParseQuery(string(body)); err == nil && bytes.ContainsRune(body, '=') && !bytes.ContainsAny(body, "{[") {
+		return redactValues(form).Encode()
+	}
+	return string(body)
+}
+
+// slugPattern matches runs of characters not allowed in cassette file names
+var slugPattern = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// slug turns a URL path into a readable file name suffix
+func slug(path string) string {
+	s := strings.Trim(slugPattern.ReplaceAllString(path, "-"), "-")
+	if len(s) > 80 {
+		s = s[:80]
+	}
+	if s == "" {
+		return ""
+	}
+	return "-" + s
+}
@@ -0,0 +1,166 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+)
+
+// Visibility types restricting who can see a comment or worklog
+const (
+	VisibilityRole  = "role"
+	VisibilityGroup = "group"
+)
+
+// Visibility restricts a comment or worklog to the members of a project
+// role or a group
+type Visibility struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// String returns the visibility as "role Developers" or "group jira-users"
+func (v *Visibility) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.Type + " " + v.Value
+}
+
+// Comment is a comment on an issue
+type Comment struct {
+	ID         string      `json:"id"`
+	Author     User        `json:"author"`
+	Body       RichText    `json:"body"`
+	Created    *time.Time  `json:"created,omitempty"`
+	Updated    *time.Time  `json:"updated,omitempty"`
+	Visibility *Visibility `json:"visibility,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, parsing Jira's timestamps
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	type comment Comment
+	var temp struct {
+		comment
+		Created string `json:"created"`
+		Updated string `json:"updated"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	*c = Comment(temp.comment)
+	c.Created = parseTime(temp.Created)
+	c.Updated = parseTime(temp.Updated)
+	return nil
+}
+
+// CommentResponse is a page of the comments of an issue
+type CommentResponse struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Comments   []Comment `json:"comments"`
+}
+
+// NewComment is the content of a comment to add or edit
+type NewComment struct {
+	// Body is Markdown, converted to the rich text format of the API version
+	Body string
+	// Visibility restricts the comment, nil makes it visible to everyone
+	// who can see the issue
+	Visibility *Visibility
+}
+
+// FetchComments returns the comments of an issue, oldest first. A limit of
+// zero or less returns every comment.
+func (c *Client) FetchComments(ctx context.Context, issueKey string, limit int) ([]Comment, error) {
+	return collect(c.Comments(ctx, issueKey), limit)
+}
+
+// Comments returns an iterator over the comments of an issue, oldest first.
+// Pages are requested from Jira as the iterator advances.
+func (c *Client) Comments(ctx context.Context, issueKey string) iter.Seq2[Comment, error] {
+	return paginate(0, func(startAt int) ([]Comment, pageInfo, error) {
+		var response CommentResponse
+		url := c.apiURL("issue/%s/comment?startAt=%d", issueKey, startAt)
+		if err := c.makeGetRequest(ctx, url, &response); err != nil {
+			return nil, pageInfo{}, fmt.Errorf("error fetching comments of issue %s: %w", issueKey, err)
+		}
+		return response.Comments, response.pageInfo(), nil
+	})
+}
+
+// GetComment returns a comment of an issue
+func (c *Client) GetComment(ctx context.Context, issueKey, id string) (Comment, error) {
+	var comment Comment
+	if err := c.makeGetRequest(ctx, c.apiURL("issue/%s/comment/%s", issueKey, id), &comment); err != nil {
+		return Comment{}, fmt.Errorf("error fetching comment %s of issue %s: %w", id, issueKey, err)
+	}
+	return comment, nil
+}
+
+// AddComment adds a comment to an issue and returns it
+func (c *Client) AddComment(ctx context.Context, issueKey string, comment NewComment) (Comment, error) {
+	payload, err := c.commentPayload(comment)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	var created Comment
+	if err := c.makePostRequest(ctx, c.apiURL("issue/%s/comment", issueKey), payload, &created); err != nil {
+		return Comment{}, fmt.Errorf("error adding comment to issue %s: %w", issueKey, err)
+	}
+	return created, nil
+}
+
+// UpdateComment replaces the body and visibility of a comment
+func (c *Client) UpdateComment(ctx context.Context, issueKey, id string, comment NewComment) (Comment, error) {
+	payload, err := c.commentPayload(comment)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	var updated Comment
+	if err := c.makePutRequest(ctx, c.apiURL("issue/%s/comment/%s", issueKey, id), payload, &updated); err != nil {
+		return Comment{}, fmt.Errorf("error updating comment %s of issue %s: %w", id, issueKey, err)
+	}
+	return updated, nil
+}
+
+// DeleteComment deletes a comment of an issue
+func (c *Client) DeleteComment(ctx context.Context, issueKey, id string) error {
+	if err := c.makeDeleteRequest(ctx, c.apiURL("issue/%s/comment/%s", issueKey, id)); err != nil {
+		return fmt.Errorf("error deleting comment %s of issue %s: %w", id, issueKey, err)
+	}
+	return nil
+}
+
+// commentPayload builds the request body of a new or edited comment
+func (c *Client) commentPayload(comment NewComment) (map[string]any, error) {
+	if strings.TrimSpace(comment.Body) == "" {
+		return nil, errors.New("a comment must not be empty")
+	}
+	payload := map[string]any{"body": c.commentText(comment.Body)}
+	if comment.Visibility != nil {
+		if err := comment.Visibility.validate(); err != nil {
+			return nil, err
+		}
+		payload["visibility"] = comment.Visibility
+	}
+	return payload, nil
+}
+
+// validate checks the visibility names a role or group
+func (v *Visibility) validate() error {
+	switch {
+	case v.Type != VisibilityRole && v.Type != VisibilityGroup:
+		return fmt.Errorf("invalid visibility type %q: expected %s or %s", v.Type, VisibilityRole, VisibilityGroup)
+	case v.Value == "":
+		return fmt.Errorf("a %s is required to restrict visibility", v.Type)
+	}
+	return nil
+}
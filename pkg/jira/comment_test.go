@@ -0,0 +1,131 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchComments(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/rest/api/2/issue/TEST-1/comment", r.URL.Path)
+		startAt := r.URL.Query().Get("startAt")
+		id := map[string]string{"0": "100", "1": "101"}[startAt]
+		fmt.Fprintf(w, `{"startAt": %s, "maxResults": 1, "total": 2, "comments": [{
+			"id": %q, "author": {"name": "jdoe", "displayName": "Jane Doe"}, "body": "Reproduced",
+			"created": "2025-06-02T10:30:00.000+0000", "updated": "2025-06-02T10:30:00.000+0000",
+			"visibility": {"type": "role", "value": "Developers"}}]}`, startAt, id)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testServerInfo)
+
+	comments, err := client.FetchComments(context.Background(), "TEST-1", 0)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, []string{"100", "101"}, []string{comments[0].ID, comments[1].ID})
+	assert.Equal(t, "Jane Doe", comments[0].Author.DisplayName)
+	assert.Equal(t, RichText("Reproduced"), comments[0].Body)
+	require.NotNil(t, comments[0].Created)
+	assert.True(t, comments[0].Created.Equal(time.Date(2025, 6, 2, 10, 30, 0, 0, time.UTC)))
+	assert.Equal(t, "role Developers", comments[0].Visibility.String())
+
+	comments, err = client.FetchComments(context.Background(), "TEST-1", 1)
+	require.NoError(t, err)
+	assert.Len(t, comments, 1)
+}
+
+func TestAddComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		version APIVersion
+		body    any
+	}{
+		{"v2 sends wiki markup", APIVersion2, "Fixed in *1.2*"},
+		{"v3 sends ADF", APIVersion3, map[string]any{"type": "doc", "version": 1.0, "content": []any{
+			map[string]any{"type": "paragraph", "content": []any{
+				map[string]any{"type": "text", "text": "Fixed in "},
+				map[string]any{"type": "text", "text": "1.2", "marks": []any{map[string]any{"type": "strong"}}},
+			}},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, fmt.Sprintf("/rest/api/%s/issue/TEST-1/comment", tt.version), r.URL.Path)
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				_, _ = w.Write([]byte(`{"id": "100"}`))
+			}))
+			defer server.Close()
+			client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), WithAPIVersion(tt.version))
+
+			comment, err := client.AddComment(context.Background(), "TEST-1", NewComment{
+				Body:       "Fixed in **1.2**",
+				Visibility: &Visibility{Type: VisibilityGroup, Value: "jira-developers"},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "100", comment.ID)
+			assert.Equal(t, map[string]any{
+				"body":       tt.body,
+				"visibility": map[string]any{"type": "group", "value": "jira-developers"},
+			}, payload)
+		})
+	}
+}
+
+func TestUpdateAndDeleteComment(t *testing.T) {
+	var putURL, deleteURL string
+	var payload any
+	client := NewClient(testBaseURL, testAuth,
+		WithPutRequestFunc(func(ctx context.Context, url string, body any, target any) error {
+			putURL, payload = url, body
+			return nil
+		}),
+		WithDeleteRequestFunc(func(ctx context.Context, url string) error {
+			deleteURL = url
+			return nil
+		}))
+
+	_, err := client.UpdateComment(context.Background(), "TEST-1", "100", NewComment{Body: "Edited"})
+	require.NoError(t, err)
+	assert.Equal(t, testBaseURL+"/rest/api/2/issue/TEST-1/comment/100", putURL)
+	assert.Equal(t, map[string]any{"body": "Edited"}, payload)
+
+	require.NoError(t, client.DeleteComment(context.Background(), "TEST-1", "100"))
+	assert.Equal(t, testBaseURL+"/rest/api/2/issue/TEST-1/comment/100", deleteURL)
+}
+
+func TestCommentErrors(t *testing.T) {
+	client := NewClient(testBaseURL, testAuth,
+		WithPostRequestFunc(func(ctx context.Context, url string, body any, target any) error {
+			t.Errorf("unexpected request %s", url)
+			return nil
+		}))
+
+	_, err := client.AddComment(context.Background(), "TEST-1", NewComment{Body: "  \n"})
+	assert.EqualError(t, err, "a comment must not be empty")
+
+	_, err = client.AddComment(context.Background(), "TEST-1", NewComment{Body: "Hi", Visibility: &Visibility{Type: "user", Value: "jdoe"}})
+	assert.EqualError(t, err, `invalid visibility type "user": expected role or group`)
+
+	_, err = client.AddComment(context.Background(), "TEST-1", NewComment{Body: "Hi", Visibility: &Visibility{Type: VisibilityRole}})
+	assert.EqualError(t, err, "a role is required to restrict visibility")
+
+	client = NewClient(testBaseURL, testAuth,
+		WithDeleteRequestFunc(func(ctx context.Context, url string) error {
+			return fmt.Errorf("404 Not Found")
+		}))
+	err = client.DeleteComment(context.Background(), "TEST-1", "100")
+	assert.EqualError(t, err, "error deleting comment 100 of issue TEST-1: 404 Not Found")
+}
@@ -0,0 +1,205 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NewIssue describes an issue to create
+type NewIssue struct {
+	Project string
+	Type    string
+	Summary string
+	// Description is Markdown, sent as an ADF document on API version 3
+	Description string
+	// Assignee is a username, account ID, email address or display name
+	Assignee   string
+	Priority   string
+	Labels     []string
+	Components []string
+	// Epic is the key of the epic the issue belongs to
+	Epic string
+	// Parent is the key of the parent issue, such as the story of a sub-task
+	Parent string
+	// Fields holds further fields by ID, name or logical name such as
+	// story_points. String values are converted to what the field expects,
+	// other values are sent as they are.
+	Fields map[string]any
+}
+
+// CreatedIssue identifies a newly created issue
+type CreatedIssue struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Self string `json:"self"`
+}
+
+// CreateIssue creates an issue and returns its key
+func (c *Client) CreateIssue(ctx context.Context, issue NewIssue) (CreatedIssue, error) {
+	switch {
+	case issue.Project == "":
+		return CreatedIssue{}, errors.New("a project is required to create an issue")
+	case issue.Type == "":
+		return CreatedIssue{}, errors.New("an issue type is required to create an issue")
+	case issue.Summary == "":
+		return CreatedIssue{}, errors.New("a summary is required to create an issue")
+	}
+
+	fields, err := c.newIssueFields(ctx, issue)
+	if err != nil {
+		return CreatedIssue{}, err
+	}
+
+	var created CreatedIssue
+	if err := c.makePostRequest(ctx, c.apiURL("issue"), map[string]any{"fields": fields}, &created); err != nil {
+		return CreatedIssue{}, fmt.Errorf("error creating issue in project %s: %w", issue.Project, err)
+	}
+	return created, nil
+}
+
+// newIssueFields builds the fields of the create request for issue
+func (c *Client) newIssueFields(ctx context.Context, issue NewIssue) (map[string]any, error) {
+	fields := map[string]any{
+		"project":   map[string]string{"key": issue.Project},
+		"issuetype": map[string]string{"name": issue.Type},
+		"summary":   issue.Summary,
+	}
+	if issue.Description != "" {
+		fields["description"] = c.richText(issue.Description)
+	}
+	if issue.Priority != "" {
+		fields["priority"] = map[string]string{"name": issue.Priority}
+	}
+	if len(issue.Labels) > 0 {
+		fields["labels"] = issue.Labels
+	}
+	if len(issue.Components) > 0 {
+		components := make([]map[string]string, len(issue.Components))
+		for i, name := range issue.Components {
+			components[i] = map[string]string{"name": name}
+		}
+		fields["components"] = components
+	}
+	if issue.Assignee != "" {
+		user, err := c.FindUser(ctx, issue.Assignee)
+		if err != nil {
+			return nil, fmt.Errorf("error finding assignee: %w", err)
+		}
+		fields["assignee"] = c.UserReference(ctx, user)
+	}
+	if issue.Parent != "" {
+		fields["parent"] = map[string]string{"key": issue.Parent}
+	}
+
+	registry := c.fieldRegistry(ctx)
+	if issue.Epic != "" {
+		// Team-managed projects on Jira Cloud have no Epic Link field, the
+		// epic is the parent of the issue there
+		switch id := registry.ID(FieldEpicLink); {
+		case id != "":
+			fields[id] = issue.Epic
+		case issue.Parent == "":
+			fields["parent"] = map[string]string{"key": issue.Epic}
+		default:
+			return nil, errors.New("cannot set both an epic and a parent: this Jira instance has no Epic Link field")
+		}
+	}
+
+	custom, err := c.encodeFields(ctx, registry, issue.Fields)
+	if err != nil {
+		return nil, err
+	}
+	for id, value := range custom {
+		fields[id] = value
+	}
+	return fields, nil
+}
+
+// encodeFields resolves the names of fields to their IDs and converts
+// string values to what each field expects
+func (c *Client) encodeFields(ctx context.Context, registry *FieldRegistry, values map[string]any) (map[string]any, error) {
+	fields := map[string]any{}
+	for name, value := range values {
+		id, ok := registry.Resolve(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		encoded, err := c.encodeFieldValue(ctx, registry, id, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for field %s: %w", name, err)
+		}
+		fields[id] = encoded
+	}
+	return fields, nil
+}
+
+// encodeFieldValue converts a string to the value the field id expects,
+// going by the schema of the field. Other values, and values of fields
+// without a known schema, are returned unchanged.
+func (c *Client) encodeFieldValue(ctx context.Context, registry *FieldRegistry, id string, value any) (any, error) {
+	s, ok := value.(string)
+	field, known := registry.Field(id)
+	if !ok || !known {
+		return value, nil
+	}
+
+	if field.Schema.Type == "array" {
+		var items []any
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			encoded, err := c.encodeScalar(ctx, field.Schema.Items, field, item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, encoded)
+		}
+		return items, nil
+	}
+	return c.encodeScalar(ctx, field.Schema.Type, field, s)
+}
+
+// encodeScalar converts s to a single value of the given schema type
+func (c *Client) encodeScalar(ctx context.Context, schemaType string, field Field, s string) (any, error) {
+	switch schemaType {
+	case "number":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", s)
+		}
+		return n, nil
+	case "option":
+		return map[string]string{"value": s}, nil
+	case "user":
+		user, err := c.FindUser(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		return c.UserReference(ctx, user), nil
+	case "project", "issuelink":
+		return map[string]string{"key": s}, nil
+	case "priority", "issuetype", "component", "version", "resolution", "group", "securitylevel":
+		return map[string]string{"name": s}, nil
+	case "string":
+		if isRichTextField(field) {
+			return c.richText(s), nil
+		}
+		return s, nil
+	default:
+		return s, nil
+	}
+}
+
+// isRichTextField reports whether field holds formatted text, which is an
+// ADF document on API version 3
+func isRichTextField(field Field) bool {
+	switch field.Schema.System {
+	case "description", "environment":
+		return true
+	}
+	return field.Schema.Custom == "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+}
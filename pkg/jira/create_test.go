@@ -0,0 +1,144 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestFields are the fields of the instance issues are created in
+var createTestFields = append([]Field{
+	{ID: "customfield_10200", Name: "Severity", Custom: true, Schema: FieldSchema{Type: "option"}},
+	{ID: "customfield_10201", Name: "Reviewer", Custom: true, Schema: FieldSchema{Type: "user"}},
+	{ID: "customfield_10202", Name: "Fix Versions", Custom: true, Schema: FieldSchema{Type: "array", Items: "version"}},
+}, testFields...)
+
+// newCreateTestServer serves the fields and users of an instance and
+// records the fields of the issue created
+func newCreateTestServer(t *testing.T, fields []Field, created *map[string]any) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/field":
+			_ = json.NewEncoder(w).Encode(fields)
+		case "/rest/api/2/user/search":
+			users := []User{
+				{Name: "jdoe", DisplayName: "Jane Doe", EmailAddress: "jane@example.com"},
+				{Name: "jdoe2", DisplayName: "John Doe", EmailAddress: "john@example.com"},
+			}
+			if r.URL.Query().Get("username") == "jane" {
+				users = users[:1]
+			}
+			_ = json.NewEncoder(w).Encode(users)
+		case "/rest/api/2/issue":
+			require.Equal(t, http.MethodPost, r.Method)
+			var payload struct {
+				Fields map[string]any `json:"fields"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			*created = payload.Fields
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "10001", "key": "TEST-7", "self": "https://jira.example.com/rest/api/2/issue/10001"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testServerInfo)
+}
+
+func TestCreateIssue(t *testing.T) {
+	var fields map[string]any
+	client := newCreateTestServer(t, createTestFields, &fields)
+
+	created, err := client.CreateIssue(context.Background(), NewIssue{
+		Project:     "TEST",
+		Type:        "Story",
+		Summary:     "Export reports as CSV",
+		Description: "Needed for **finance**",
+		Assignee:    "jane",
+		Priority:    "High",
+		Labels:      []string{"reports"},
+		Components:  []string{"CLI"},
+		Epic:        "TEST-1",
+		Fields: map[string]any{
+			"story_points": "3",
+			"Severity":     "Major",
+			"reviewer":     "john@example.com",
+			"Fix Versions": "1.0, 1.1",
+			"Team":         "Owls",
+			"Sprint":       []any{42},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "TEST-7", created.Key)
+
+	want := map[string]any{
+		"project":           map[string]any{"key": "TEST"},
+		"issuetype":         map[string]any{"name": "Story"},
+		"summary":           "Export reports as CSV",
+		"description":       "Needed for **finance**",
+		"assignee":          map[string]any{"name": "jdoe"},
+		"priority":          map[string]any{"name": "High"},
+		"labels":            []any{"reports"},
+		"components":        []any{map[string]any{"name": "CLI"}},
+		"customfield_10014": "TEST-1",
+		"customfield_10016": 3.0,
+		"customfield_10200": map[string]any{"value": "Major"},
+		"customfield_10201": map[string]any{"name": "jdoe2"},
+		"customfield_10202": []any{map[string]any{"name": "1.0"}, map[string]any{"name": "1.1"}},
+		"customfield_10100": "Owls",
+		"customfield_10020": []any{42.0},
+	}
+	assert.Equal(t, want, fields)
+}
+
+func TestCreateIssueEpicAsParent(t *testing.T) {
+	// Team-managed projects have no Epic Link field
+	var noEpicLink []Field
+	for _, field := range createTestFields {
+		if field.ID != "customfield_10014" {
+			noEpicLink = append(noEpicLink, field)
+		}
+	}
+	var fields map[string]any
+	client := newCreateTestServer(t, noEpicLink, &fields)
+
+	_, err := client.CreateIssue(context.Background(), NewIssue{Project: "TEST", Type: "Task", Summary: "Task", Epic: "TEST-1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "TEST-1"}, fields["parent"])
+
+	_, err = client.CreateIssue(context.Background(), NewIssue{Project: "TEST", Type: "Sub-task", Summary: "Task", Epic: "TEST-1", Parent: "TEST-2"})
+	assert.ErrorContains(t, err, "cannot set both an epic and a parent")
+}
+
+func TestCreateIssueErrors(t *testing.T) {
+	var fields map[string]any
+	client := newCreateTestServer(t, createTestFields, &fields)
+
+	tests := []struct {
+		name  string
+		issue NewIssue
+		err   string
+	}{
+		{name: "no project", issue: NewIssue{Type: "Task", Summary: "Task"}, err: "a project is required"},
+		{name: "no summary", issue: NewIssue{Project: "TEST", Type: "Task"}, err: "a summary is required"},
+		{name: "ambiguous assignee", issue: NewIssue{Project: "TEST", Type: "Task", Summary: "Task", Assignee: "doe"}, err: `2 users match "doe": Jane Doe, John Doe`},
+		{name: "unknown field", issue: NewIssue{Project: "TEST", Type: "Task", Summary: "Task", Fields: map[string]any{"Colour": "red"}}, err: `unknown field "Colour"`},
+		{name: "not a number", issue: NewIssue{Project: "TEST", Type: "Task", Summary: "Task", Fields: map[string]any{"story_points": "many"}}, err: "expected a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields = nil
+			_, err := client.CreateIssue(context.Background(), tt.issue)
+			assert.ErrorContains(t, err, tt.err)
+			assert.Nil(t, fields, "no issue is created")
+		})
+	}
+}
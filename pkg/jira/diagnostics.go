@@ -0,0 +1,94 @@
+package jira
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ConnectionReport describes a test request sent to the Jira server
+type ConnectionReport struct {
+	URL        string
+	StatusCode int
+	Protocol   string
+	Duration   time.Duration
+
+	// TLS is nil for plain HTTP connections
+	TLS *TLSReport
+}
+
+// TLSReport describes the negotiated TLS session and the server certificate
+type TLSReport struct {
+	Version     string
+	CipherSuite string
+	ServerName  string
+	Subject     string
+	Issuer      string
+	NotAfter    time.Time
+}
+
+// CheckConnection sends a single authenticated request to the server info
+// endpoint, bypassing retries and rate limiting, and reports how the
+// connection was established. A non-2xx status is reported, not returned as
+// an error, so that TLS problems can be told apart from credential problems.
+func (c *Client) CheckConnection(ctx context.Context) (*ConnectionReport, error) {
+	url := c.apiURL("serverInfo")
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.authenticate(req); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	report := &ConnectionReport{
+		URL:        req.URL.Redacted(),
+		StatusCode: resp.StatusCode,
+		Protocol:   resp.Proto,
+		Duration:   time.Since(start),
+	}
+	if resp.TLS != nil {
+		report.TLS = newTLSReport(resp.TLS)
+	}
+	return report, nil
+}
+
+// newTLSReport summarises a TLS connection state
+func newTLSReport(state *tls.ConnectionState) *TLSReport {
+	report := &TLSReport{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		report.Subject = cert.Subject.String()
+		report.Issuer = cert.Issuer.String()
+		report.NotAfter = cert.NotAfter
+	}
+	return report
+}
+
+// ClientCertificateInfo returns the leaf of the configured client
+// certificate, or nil when mutual TLS is not configured
+func (o TransportOptions) ClientCertificateInfo() (*x509.Certificate, error) {
+	if o.ClientCert == "" {
+		return nil, nil
+	}
+	pair, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey)
+	if err != nil {
+		return nil, fmt.Errorf("error loading client certificate: %w", err)
+	}
+	return x509.ParseCertificate(pair.Certificate[0])
+}
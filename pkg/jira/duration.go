@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jira's default time tracking settings, under which a day of work is eight
+// hours and a week five days
+const (
+	WorkDay  = 8 * time.Hour
+	WorkWeek = 5 * WorkDay
+)
+
+// durationUnits are the units of Jira durations in the order they are written
+var durationUnits = []struct {
+	symbol string
+	length time.Duration
+}{
+	{"w", WorkWeek},
+	{"d", WorkDay},
+	{"h", time.Hour},
+	{"m", time.Minute},
+}
+
+var durationPart = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([wdhm])`)
+
+// ParseDuration parses a duration in Jira's syntax, such as "1w 2d 3h 4m"
+// or "1h30m". Weeks and days are work weeks and work days.
+func ParseDuration(s string) (time.Duration, error) {
+	rest := strings.ToLower(strings.TrimSpace(s))
+	if rest == "" {
+		return 0, fmt.Errorf("invalid duration %q: expected a duration such as 1w 2d 3h 4m", s)
+	}
+
+	var total time.Duration
+	for rest != "" {
+		match := durationPart.FindStringSubmatch(rest)
+		if match == nil {
+			return 0, fmt.Errorf("invalid duration %q: expected a duration such as 1w 2d 3h 4m", s)
+		}
+		amount, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		for _, unit := range durationUnits {
+			if unit.symbol == match[2] {
+				total += time.Duration(amount * float64(unit.length))
+			}
+		}
+		rest = strings.TrimSpace(rest[len(match[0]):])
+	}
+	return total.Round(time.Minute), nil
+}
+
+// FormatDuration formats a duration in Jira's syntax, such as "1d 3h 30m",
+// the inverse of ParseDuration. Durations under a minute format as "0m".
+func FormatDuration(d time.Duration) string {
+	var parts []string
+	d = d.Round(time.Minute)
+	for _, unit := range durationUnits {
+		if count := d / unit.length; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", count, unit.symbol))
+			d -= count * unit.length
+		}
+	}
+	if parts == nil {
+		return "0m"
+	}
+	return strings.Join(parts, " ")
+}
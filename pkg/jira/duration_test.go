@@ -0,0 +1,46 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1w 2d 3h 4m", WorkWeek + 2*WorkDay + 3*time.Hour + 4*time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{" 2H 15M ", 2*time.Hour + 15*time.Minute, false},
+		{"1.5h", 90 * time.Minute, false},
+		{"1d", 8 * time.Hour, false},
+		{"45m", 45 * time.Minute, false},
+		{"", 0, true},
+		{"90", 0, true},
+		{"1h 30s", 0, true},
+		{"h", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "1w 2d 3h 4m", FormatDuration(WorkWeek+2*WorkDay+3*time.Hour+4*time.Minute))
+	assert.Equal(t, "1h 30m", FormatDuration(90*time.Minute))
+	assert.Equal(t, "1d", FormatDuration(8*time.Hour))
+	assert.Equal(t, "0m", FormatDuration(20*time.Second))
+}
@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// maxErrorBodySize limits how much of an error response body is read
+const maxErrorBodySize = 1 << 20
+
+// APIError represents a non-2xx response returned by the Jira REST API
+type APIError struct {
+	StatusCode    int               `json:"-"`
+	Method        string            `json:"-"`
+	URL           string            `json:"-"`
+	ErrorMessages []string          `json:"errorMessages"`
+	Errors        map[string]string `json:"errors"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s returned %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if details := e.Messages(); len(details) > 0 {
+		msg += ": " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+// Messages returns Jira's error messages followed by the field errors,
+// sorted by field name so the output is stable.
+func (e *APIError) Messages() []string {
+	messages := append([]string{}, e.ErrorMessages...)
+
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, e.Errors[field]))
+	}
+	return messages
+}
+
+// newAPIError builds an APIError from an unsuccessful response.
+// Bodies that are not Jira's JSON error format (e.g. HTML error pages) are ignored.
+func newAPIError(req *http.Request, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		URL:        req.URL.Redacted(),
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil && len(body) > 0 {
+		_ = json.Unmarshal(body, apiErr)
+	}
+	return apiErr
+}
+
+// checkResponse returns an APIError if the response status is not 2xx
+func checkResponse(req *http.Request, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return newAPIError(req, resp)
+}
+
+// hasStatus reports whether err is an APIError with the given status code
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsUnauthorized reports whether err was caused by missing or invalid credentials
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err was caused by insufficient permissions
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err was caused by a missing resource
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err was caused by Jira rate limiting the client
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsServerError reports whether err was caused by a 5xx response from Jira
+func IsServerError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
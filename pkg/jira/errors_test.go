@@ -0,0 +1,121 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiErr   *APIError
+		expected string
+	}{
+		{
+			name:     "status only",
+			apiErr:   &APIError{StatusCode: 500, Method: "GET", URL: "https://jira/rest/api/2/search"},
+			expected: "GET https://jira/rest/api/2/search returned 500 Internal Server Error",
+		},
+		{
+			name: "error messages and field errors",
+			apiErr: &APIError{
+				StatusCode:    400,
+				Method:        "POST",
+				URL:           "https://jira/rest/api/2/issue",
+				ErrorMessages: []string{"Invalid request"},
+				Errors:        map[string]string{"summary": "required", "project": "unknown"},
+			},
+			expected: "POST https://jira/rest/api/2/issue returned 400 Bad Request: Invalid request; project: unknown; summary: required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.apiErr.Error())
+		})
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		expectedMessages []string
+	}{
+		{
+			name:             "jira error body",
+			body:             `{"errorMessages":["Issue does not exist"],"errors":{}}`,
+			expectedMessages: []string{"Issue does not exist"},
+		},
+		{
+			name:             "html error page",
+			body:             "<html><body>Bad Gateway</body></html>",
+			expectedMessages: []string{},
+		},
+		{
+			name:             "empty body",
+			body:             "",
+			expectedMessages: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://jira.example.com/rest/api/2/issue/TEST-1", nil)
+			resp := &http.Response{StatusCode: 404, Body: io.NopCloser(strings.NewReader(tt.body))}
+
+			apiErr := newAPIError(req, resp)
+			assert.Equal(t, 404, apiErr.StatusCode)
+			assert.Equal(t, "GET", apiErr.Method)
+			assert.Equal(t, "https://jira.example.com/rest/api/2/issue/TEST-1", apiErr.URL)
+			assert.Equal(t, tt.expectedMessages, apiErr.Messages())
+		})
+	}
+}
+
+func TestErrorStatusChecks(t *testing.T) {
+	wrap := func(statusCode int) error {
+		return fmt.Errorf("error fetching issue TEST-1: %w", &APIError{StatusCode: statusCode})
+	}
+
+	assert.True(t, IsUnauthorized(wrap(401)))
+	assert.True(t, IsForbidden(wrap(403)))
+	assert.True(t, IsNotFound(wrap(404)))
+	assert.True(t, IsRateLimited(wrap(429)))
+	assert.True(t, IsServerError(wrap(503)))
+
+	assert.False(t, IsNotFound(wrap(401)))
+	assert.False(t, IsServerError(wrap(429)))
+	assert.False(t, IsNotFound(errors.New("not found")))
+	assert.False(t, IsUnauthorized(nil))
+}
+
+func TestRequestsReturnAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()))
+
+	_, err := client.GetIssue(context.Background(), "TEST-404")
+	assert.True(t, IsNotFound(err))
+	assert.Contains(t, err.Error(), "Issue does not exist")
+
+	err = client.UpdateIssueStatus(context.Background(), "TEST-404", "31")
+	assert.True(t, IsNotFound(err))
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "POST", apiErr.Method)
+}
@@ -0,0 +1,339 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Logical names of the custom fields owlify understands. Every Jira
+// instance stores them under its own customfield_* IDs.
+const (
+	FieldStoryPoints   = "story_points"
+	FieldEpicLink      = "epic_link"
+	FieldSprint        = "sprint"
+	FieldParentFeature = "parent_feature"
+)
+
+// LogicalFields returns the logical field names, sorted
+func LogicalFields() []string {
+	return []string{FieldEpicLink, FieldParentFeature, FieldSprint, FieldStoryPoints}
+}
+
+// fieldSchemas identifies logical fields by the custom type of the plugin
+// providing them, which does not depend on the language of the instance
+var fieldSchemas = map[string]string{
+	FieldEpicLink:      "com.pyxis.greenhopper.jira:gh-epic-link",
+	FieldSprint:        "com.pyxis.greenhopper.jira:gh-sprint",
+	FieldParentFeature: "com.atlassian.jpo:jpo-custom-field-parent",
+}
+
+// fieldNames are the names logical fields commonly have, in order of preference
+var fieldNames = map[string][]string{
+	FieldStoryPoints:   {"Story Points", "Story point estimate"},
+	FieldEpicLink:      {"Epic Link"},
+	FieldSprint:        {"Sprint"},
+	FieldParentFeature: {"Parent Feature", "Parent Link"},
+}
+
+// Field is a system or custom field of a Jira instance
+type Field struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Custom bool        `json:"custom"`
+	Schema FieldSchema `json:"schema"`
+}
+
+// FieldSchema describes the values a field holds
+type FieldSchema struct {
+	Type   string `json:"type"`
+	Items  string `json:"items,omitempty"`
+	System string `json:"system,omitempty"`
+	Custom string `json:"custom,omitempty"`
+}
+
+// FieldRegistry resolves logical field names such as story_points, and
+// field names as shown in Jira, to the field IDs of one instance
+type FieldRegistry struct {
+	ids    map[string]string
+	byName map[string]Field
+	byID   map[string]Field
+}
+
+// NewFieldRegistry creates a registry from the fields of an instance.
+// IDs in overrides, keyed by logical name, take precedence over the ones
+// found in fields.
+func NewFieldRegistry(fields []Field, overrides map[string]string) *FieldRegistry {
+	r := &FieldRegistry{
+		ids:    map[string]string{},
+		byName: map[string]Field{},
+		byID:   map[string]Field{},
+	}
+	for _, field := range fields {
+		r.byID[field.ID] = field
+		// System fields win over custom fields of the same name
+		if existing, ok := r.byName[strings.ToLower(field.Name)]; !ok || existing.Custom {
+			r.byName[strings.ToLower(field.Name)] = field
+		}
+	}
+
+	for _, logical := range LogicalFields() {
+		if id := r.discover(fields, logical); id != "" {
+			r.ids[logical] = id
+		}
+	}
+	for name, id := range overrides {
+		if id != "" {
+			r.ids[strings.ToLower(name)] = id
+		}
+	}
+	return r
+}
+
+// discover finds the ID of a logical field by its plugin type, then by name
+func (r *FieldRegistry) discover(fields []Field, logical string) string {
+	if schema, ok := fieldSchemas[logical]; ok {
+		for _, field := range fields {
+			if field.Schema.Custom == schema {
+				return field.ID
+			}
+		}
+	}
+	for _, name := range fieldNames[logical] {
+		if field, ok := r.byName[strings.ToLower(name)]; ok {
+			return field.ID
+		}
+	}
+	return ""
+}
+
+// ID returns the field ID of a logical field, or "" when the instance does
+// not have it
+func (r *FieldRegistry) ID(logical string) string {
+	return r.ids[logical]
+}
+
+// IDs returns the IDs of the logical fields the instance has
+func (r *FieldRegistry) IDs(logicals ...string) []string {
+	var ids []string
+	for _, logical := range logicals {
+		if id := r.ID(logical); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Resolve returns the field ID for a logical field name, a field ID or a
+// field name as shown in Jira, ignoring case
+func (r *FieldRegistry) Resolve(name string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if id, ok := r.ids[strings.ReplaceAll(key, " ", "_")]; ok {
+		return id, true
+	}
+	if _, ok := r.byID[key]; ok {
+		return key, true
+	}
+	if field, ok := r.byName[key]; ok {
+		return field.ID, true
+	}
+	return "", false
+}
+
+// Field returns the field with the given ID
+func (r *FieldRegistry) Field(id string) (Field, bool) {
+	field, ok := r.byID[id]
+	return field, ok
+}
+
+// Name returns the name of the field with the given ID as shown in Jira
+func (r *FieldRegistry) Name(id string) string {
+	if field, ok := r.byID[id]; ok {
+		return field.Name
+	}
+	return id
+}
+
+// Mapping is the field ID a logical field resolves to
+type Mapping struct {
+	Field string `json:"field"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+}
+
+// Mappings returns the field ID of every logical field and override, sorted
+func (r *FieldRegistry) Mappings() []Mapping {
+	var mappings []Mapping
+	for logical, id := range r.ids {
+		mappings = append(mappings, Mapping{Field: logical, ID: id, Name: r.Name(id)})
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Field < mappings[j].Field })
+	return mappings
+}
+
+// WithFieldIDs sets the field IDs of logical fields, such as story_points,
+// overriding the ones discovered from the instance. When every logical
+// field is given, the field endpoint is not requested at all.
+func WithFieldIDs(ids map[string]string) ClientOption {
+	return func(c *Client) {
+		c.fieldIDs = ids
+	}
+}
+
+// Fields returns every system and custom field of the instance
+func (c *Client) Fields(ctx context.Context) ([]Field, error) {
+	var fields []Field
+	if err := c.makeGetRequest(ctx, c.apiURL("field"), &fields); err != nil {
+		return nil, fmt.Errorf("error fetching fields: %w", err)
+	}
+	return fields, nil
+}
+
+// FieldRegistry returns the registry resolving logical fields for the
+// instance. The field endpoint is only requested once per client.
+func (c *Client) FieldRegistry(ctx context.Context) (*FieldRegistry, error) {
+	c.fieldsMu.Lock()
+	defer c.fieldsMu.Unlock()
+	if c.fields != nil {
+		return c.fields, nil
+	}
+	if c.fieldsErr != nil {
+		return nil, c.fieldsErr
+	}
+
+	var fields []Field
+	if !c.fieldsOverridden() {
+		var err error
+		if fields, err = c.Fields(ctx); err != nil {
+			// A cancelled request may succeed later, anything else would fail again
+			if ctx.Err() == nil {
+				c.fieldsErr = err
+			}
+			return nil, err
+		}
+	}
+	c.fields = NewFieldRegistry(fields, c.fieldIDs)
+	return c.fields, nil
+}
+
+// fieldsOverridden reports whether WithFieldIDs gave every logical field
+func (c *Client) fieldsOverridden() bool {
+	for _, logical := range LogicalFields() {
+		if c.fieldIDs[logical] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldRegistry returns the field registry, falling back to the configured
+// field IDs alone when the fields of the instance cannot be fetched
+func (c *Client) fieldRegistry(ctx context.Context) *FieldRegistry {
+	registry, err := c.FieldRegistry(ctx)
+	if err != nil {
+		c.logger.DebugContext(ctx, "Could not discover custom fields, using configured field IDs only", "error", err)
+		return NewFieldRegistry(nil, c.fieldIDs)
+	}
+	return registry
+}
+
+// resolveFields fills in the fields of issues that are kept in custom fields
+func (c *Client) resolveFields(ctx context.Context, issues []Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	registry := c.fieldRegistry(ctx)
+	for i := range issues {
+		issues[i].Fields.resolve(registry)
+	}
+}
+
+// customFields returns the customfield_* values of a JSON object of fields
+func customFields(data []byte) (map[string]json.RawMessage, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	custom := map[string]json.RawMessage{}
+	for id, value := range all {
+		if strings.HasPrefix(id, "customfield_") {
+			custom[id] = value
+		}
+	}
+	return custom, nil
+}
+
+// customValue returns the value of the custom field id, nil when it is
+// missing or null
+func customValue(custom map[string]json.RawMessage, id string) json.RawMessage {
+	value, ok := custom[id]
+	if !ok || id == "" || string(value) == "null" {
+		return nil
+	}
+	return value
+}
+
+// decodeIssueKey reads an issue key from a custom field. Fields linking to
+// issues hold the key itself, or an object with the key or its data.
+func decodeIssueKey(value json.RawMessage) string {
+	var key string
+	if err := json.Unmarshal(value, &key); err == nil {
+		return key
+	}
+	var link struct {
+		Key  string `json:"key"`
+		Data struct {
+			Key string `json:"key"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(value, &link); err != nil {
+		return ""
+	}
+	if link.Key != "" {
+		return link.Key
+	}
+	return link.Data.Key
+}
+
+// legacySprint matches the attributes of a sprint serialized the way Jira
+// Server returns them: "com.atlassian.greenhopper.service.sprint.Sprint@1[id=1,state=ACTIVE,name=Sprint 1,...]"
+var legacySprint = regexp.MustCompile(`(\w+)=([^,\]]*)`)
+
+// decodeSprintName returns the name of the active sprint in a sprint field,
+// else the name of the last sprint the issue was in
+func decodeSprintName(value json.RawMessage) string {
+	var items []json.RawMessage
+	if err := json.Unmarshal(value, &items); err != nil {
+		return ""
+	}
+
+	name := ""
+	for _, item := range items {
+		var sprint struct {
+			Name  string `json:"name"`
+			State string `json:"state"`
+		}
+		var legacy string
+		if err := json.Unmarshal(item, &legacy); err == nil {
+			for _, match := range legacySprint.FindAllStringSubmatch(legacy, -1) {
+				switch match[1] {
+				case "name":
+					sprint.Name = match[2]
+				case "state":
+					sprint.State = match[2]
+				}
+			}
+		} else if err := json.Unmarshal(item, &sprint); err != nil {
+			continue
+		}
+
+		name = sprint.Name
+		if strings.EqualFold(sprint.State, string(SprintStateActive)) {
+			break
+		}
+	}
+	return name
+}
@@ -1,13 +1,156 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+var testFields = []Field{
+	{ID: "summary", Name: "Summary", Schema: FieldSchema{Type: "string", System: "summary"}},
+	{ID: "customfield_10016", Name: "Story point estimate", Custom: true, Schema: FieldSchema{Type: "number"}},
+	{ID: "customfield_10014", Name: "Epic-Verknüpfung", Custom: true, Schema: FieldSchema{Type: "any", Custom: "com.pyxis.greenhopper.jira:gh-epic-link"}},
+	{ID: "customfield_10020", Name: "Sprint", Custom: true, Schema: FieldSchema{Type: "array", Items: "json", Custom: "com.pyxis.greenhopper.jira:gh-sprint"}},
+	{ID: "customfield_10100", Name: "Team", Custom: true, Schema: FieldSchema{Type: "string"}},
+}
+
+func TestFieldRegistry(t *testing.T) {
+	registry := NewFieldRegistry(testFields, map[string]string{FieldParentFeature: "customfield_12313140"})
+
+	assert.Equal(t, "customfield_10016", registry.ID(FieldStoryPoints), "found by name")
+	assert.Equal(t, "customfield_10014", registry.ID(FieldEpicLink), "found by plugin type")
+	assert.Equal(t, "customfield_10020", registry.ID(FieldSprint))
+	assert.Equal(t, "customfield_12313140", registry.ID(FieldParentFeature), "configured")
+	assert.Equal(t, []string{"customfield_10016", "customfield_10020"}, registry.IDs(FieldStoryPoints, "unknown", FieldSprint))
+
+	for name, want := range map[string]string{
+		"story_points":      "customfield_10016",
+		"Story Points":      "customfield_10016",
+		"team":              "customfield_10100",
+		"customfield_10100": "customfield_10100",
+		"Summary":           "summary",
+	} {
+		id, ok := registry.Resolve(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, want, id, name)
+	}
+	_, ok := registry.Resolve("Nonexistent")
+	assert.False(t, ok)
+
+	overridden := NewFieldRegistry(testFields, map[string]string{FieldStoryPoints: "customfield_1"})
+	assert.Equal(t, "customfield_1", overridden.ID(FieldStoryPoints))
+}
+
+func TestFieldsResolve(t *testing.T) {
+	registry := NewFieldRegistry(testFields, nil)
+	tests := []struct {
+		name   string
+		json   string
+		points float64
+		epic   string
+		sprint string
+	}{
+		{
+			name:   "cloud",
+			json:   `{"customfield_10016": 5, "customfield_10014": "EPIC-1", "customfield_10020": [{"name": "Sprint 1", "state": "closed"}, {"name": "Sprint 2", "state": "active"}]}`,
+			points: 5, epic: "EPIC-1", sprint: "Sprint 2",
+		},
+		{
+			name:   "server sprint strings",
+			json:   `{"customfield_10020": ["com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=7,rapidViewId=3,state=CLOSED,name=Sprint 7,startDate=2024-01-01]"]}`,
+			sprint: "Sprint 7",
+		},
+		{
+			name: "agile epic wins",
+			json: `{"epic": {"key": "EPIC-9", "summary": "Agile"}, "customfield_10014": "EPIC-1", "customfield_10016": null}`,
+			epic: "EPIC-9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields Fields
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &fields))
+			fields.resolve(registry)
+
+			assert.Equal(t, tt.points, fields.StoryPoint)
+			assert.Equal(t, tt.sprint, fields.Sprint)
+			if tt.epic == "" {
+				assert.Nil(t, fields.Epic)
+			} else {
+				require.NotNil(t, fields.Epic)
+				assert.Equal(t, tt.epic, fields.Epic.Key)
+			}
+		})
+	}
+}
+
+func TestFeatureResponseResolve(t *testing.T) {
+	registry := NewFieldRegistry(nil, map[string]string{FieldParentFeature: "customfield_12313140"})
+	for _, value := range []string{`"FEAT-1"`, `{"key": "FEAT-1"}`, `{"hasEpicLinkFieldDependency": false, "data": {"id": 1, "key": "FEAT-1"}}`} {
+		var epic EpicResponse
+		require.NoError(t, json.Unmarshal([]byte(`{"key": "EPIC-1", "fields": {"summary": "Epic", "customfield_12313140": `+value+`}}`), &epic))
+		epic.Feature.resolve(registry)
+		assert.Equal(t, "FEAT-1", epic.Feature.Key, value)
+	}
+}
+
+func TestFetchSprintIssuesDiscoversFields(t *testing.T) {
+	fieldRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/field":
+			fieldRequests++
+			_ = json.NewEncoder(w).Encode(testFields)
+		case "/rest/agile/1.0/sprint/1/issue":
+			fields := strings.Split(r.URL.Query().Get("fields"), ",")
+			assert.Contains(t, fields, "customfield_10016")
+			assert.Contains(t, fields, "customfield_10014")
+			assert.Contains(t, fields, "customfield_10020")
+			_, _ = w.Write([]byte(`{"total": 1, "issues": [{"key": "TEST-1", "fields": {"summary": "Story", "customfield_10016": 3}}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testServerInfo)
+
+	for i := 0; i < 2; i++ {
+		issues, err := client.FetchSprintIssues(context.Background(), 1, false)
+		require.NoError(t, err)
+		require.Len(t, issues, 1)
+		assert.Equal(t, 3.0, issues[0].Fields.StoryPoint)
+	}
+	assert.Equal(t, 1, fieldRequests)
+}
+
+func TestFieldRegistryFallsBackToConfiguredIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/field" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"total": 1, "issues": [{"key": "TEST-1", "fields": {"customfield_1": 8}}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testServerInfo,
+		WithFieldIDs(map[string]string{FieldStoryPoints: "customfield_1"}))
+
+	_, err := client.FieldRegistry(context.Background())
+	assert.Error(t, err)
+
+	issues, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", 0)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, 8.0, issues[0].Fields.StoryPoint)
+}
+
 func TestFieldsUnmarshalJSON(t *testing.T) {
 	// Test with different date formats
 	testCases := []struct {
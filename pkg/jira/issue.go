@@ -1,16 +1,22 @@
 package jira
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
 )
 
-func GetIssue(issueKey string, makeGetRequest JiraRequestFunc) (Issue, error) {
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s", jiraBaseURL, issueKey)
+func (c *Client) GetIssue(ctx context.Context, issueKey string) (Issue, error) {
+	url := c.apiURL("issue/%s", issueKey)
 
 	var issueData Issue
-	if err := makeGetRequest(url, &issueData); err != nil {
-		return Issue{}, fmt.Errorf("error fetching issue %s: %v", issueKey, err)
+	if err := c.makeGetRequest(ctx, url, &issueData); err != nil {
+		return Issue{}, fmt.Errorf("error fetching issue %s: %w", issueKey, err)
+	}
+	// Only look up the field IDs when there are custom fields to resolve
+	if len(issueData.Fields.custom) > 0 {
+		issueData.Fields.resolve(c.fieldRegistry(ctx))
 	}
 
 	return issueData, nil
@@ -18,32 +24,24 @@ func GetIssue(issueKey string, makeGetRequest JiraRequestFunc) (Issue, error) {
 
 // EpicFetcher defines an interface for fetching epic details
 type EpicFetcher interface {
-	FetchEpic(epicKey string) (EpicResponse, error)
-}
-
-// DefaultEpicFetcher implements EpicFetcher using JiraRequestFunc
-type DefaultEpicFetcher struct {
-	makeGetRequest JiraRequestFunc
+	FetchEpic(ctx context.Context, epicKey string) (EpicResponse, error)
 }
 
-// NewEpicFetcher creates a new DefaultEpicFetcher
-func NewEpicFetcher(makeGetRequest JiraRequestFunc) EpicFetcher {
-	return &DefaultEpicFetcher{makeGetRequest: makeGetRequest}
-}
-
-// FetchEpic fetches the epic details for the given issue key
-func (f *DefaultEpicFetcher) FetchEpic(epicKey string) (EpicResponse, error) {
-	return GetEpic(epicKey, f.makeGetRequest)
+// FetchEpic fetches the epic details for the given issue key.
+// It makes Client satisfy the EpicFetcher interface.
+func (c *Client) FetchEpic(ctx context.Context, epicKey string) (EpicResponse, error) {
+	return c.GetEpic(ctx, epicKey)
 }
 
 // GetEpic fetches the epic details for the given issue key.
-func GetEpic(issueKey string, makeGetRequest JiraRequestFunc) (EpicResponse, error) {
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s", jiraBaseURL, issueKey)
+func (c *Client) GetEpic(ctx context.Context, issueKey string) (EpicResponse, error) {
+	url := c.apiURL("issue/%s", issueKey)
 
 	var issueData EpicResponse
-	if err := makeGetRequest(url, &issueData); err != nil {
-		return EpicResponse{}, fmt.Errorf("error fetching issue %s: %v", issueKey, err)
+	if err := c.makeGetRequest(ctx, url, &issueData); err != nil {
+		return EpicResponse{}, fmt.Errorf("error fetching issue %s: %w", issueKey, err)
 	}
+	issueData.Feature.resolve(c.fieldRegistry(ctx))
 
 	return issueData, nil
 }
@@ -60,7 +58,7 @@ func GetValidTransitionID(status string, transitions []Transition) string {
 	return transitionName
 }
 
-func UpdateIssueStatus(issueKey string, newStatus string, makePostRequest JiraPostRequestFunc) error {
+func (c *Client) UpdateIssueStatus(ctx context.Context, issueKey string, newStatus string) error {
 	// Create the transition payload
 	payload := UpdateTransition{
 		Transition: struct {
@@ -68,21 +66,154 @@ func UpdateIssueStatus(issueKey string, newStatus string, makePostRequest JiraPo
 		}{ID: newStatus},
 	}
 
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", jiraBaseURL, issueKey)
-	if err := makePostRequest(url, payload, nil); err != nil {
-		return fmt.Errorf("error transitioning issue: %v", err)
+	url := c.apiURL("issue/%s/transitions", issueKey)
+	if err := c.makePostRequest(ctx, url, payload, nil); err != nil {
+		return fmt.Errorf("error transitioning issue: %w", err)
 	}
 
 	return nil
 }
 
-func GetAvailableTransitions(issue Issue, makeGetRequest JiraRequestFunc) ([]Transition, error) {
-	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", jiraBaseURL, issue.Key)
+// TransitionOptions are set while transitioning an issue, on the screen of
+// the transition
+type TransitionOptions struct {
+	// Resolution is the name of the resolution, such as Fixed
+	Resolution string
+	// Comment is Markdown added as a comment along with the transition
+	Comment string
+	// Fields holds further fields of the screen by ID, name or logical name,
+	// converted like the fields of NewIssue
+	Fields map[string]any
+}
+
+// FindTransition returns the transition with the given name, else the one
+// leading to the status with that name, ignoring case
+func FindTransition(name string, transitions []Transition) (Transition, bool) {
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	for _, t := range transitions {
+		if strings.EqualFold(t.To.Name, name) {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// RequiredFields returns the names of the fields that must be set on the
+// screen of the transition because they have no default value
+func (t Transition) RequiredFields() []string {
+	return t.missingFields(nil)
+}
+
+// missingFields returns the names of the required fields without a default
+// value that are not in fields, sorted
+func (t Transition) missingFields(fields map[string]any) []string {
+	var names []string
+	for id, field := range t.Fields {
+		if _, ok := fields[id]; !ok && field.Required && !field.HasDefaultValue {
+			names = append(names, field.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TransitionIssue moves an issue through transition, setting the fields of
+// its screen from options. Fields the transition requires but options do
+// not set are reported before anything is sent.
+func (c *Client) TransitionIssue(ctx context.Context, issueKey string, transition Transition, options TransitionOptions) error {
+	screen := EditMeta{Fields: transition.Fields}
+	registry := screen.registry(c.fieldIDs)
+
+	fields := map[string]any{}
+	if options.Resolution != "" {
+		fields["resolution"] = map[string]string{"name": options.Resolution}
+	}
+	for name := range options.Fields {
+		if _, ok := registry.Resolve(name); !ok {
+			return fmt.Errorf("field %s is not on the screen of transition %s", name, transition.Name)
+		}
+	}
+	custom, err := c.encodeFields(ctx, registry, options.Fields)
+	if err != nil {
+		return err
+	}
+	for id, value := range custom {
+		fields[id] = value
+	}
+
+	// Transitions fetched without their fields cannot be checked
+	if transition.Fields != nil {
+		if err := screen.validate(fields, nil); err != nil {
+			return fmt.Errorf("cannot transition issue %s to %s: %w", issueKey, transition.To.Name, err)
+		}
+		if missing := transition.missingFields(fields); len(missing) > 0 {
+			return fmt.Errorf("transition %s of issue %s requires %s", transition.Name, issueKey, strings.Join(missing, ", "))
+		}
+	}
+
+	payload := UpdateTransition{}
+	payload.Transition.ID = transition.ID
+	if len(fields) > 0 {
+		payload.Fields = fields
+	}
+	if options.Comment != "" {
+		payload.Update = map[string]any{
+			"comment": []map[string]any{{"add": map[string]any{"body": c.commentText(options.Comment)}}},
+		}
+	}
+
+	url := c.apiURL("issue/%s/transitions", issueKey)
+	if err := c.makePostRequest(ctx, url, payload, nil); err != nil {
+		return fmt.Errorf("error transitioning issue %s to %s: %w", issueKey, transition.To.Name, err)
+	}
+	return nil
+}
+
+// GetAvailableTransitions returns the transitions of an issue with the
+// fields of their screens
+func (c *Client) GetAvailableTransitions(ctx context.Context, issue Issue) ([]Transition, error) {
+	url := c.apiURL("issue/%s/transitions?expand=transitions.fields", issue.Key)
 
 	var response TransitionResponse
-	if err := makeGetRequest(url, &response); err != nil {
-		return nil, fmt.Errorf("error fetching transitions for issue %s: %v", issue.Key, err)
+	if err := c.makeGetRequest(ctx, url, &response); err != nil {
+		return nil, fmt.Errorf("error fetching transitions for issue %s: %w", issue.Key, err)
 	}
 
 	return response.Transitions, nil
 }
+
+// GetIssueDescription fetches the description of an issue. On API v3 the
+// ADF document is converted to Markdown.
+func (c *Client) GetIssueDescription(ctx context.Context, issueKey string) (RichText, error) {
+	url := c.apiURL("issue/%s?fields=description", issueKey)
+
+	var response struct {
+		Fields struct {
+			Description RichText `json:"description"`
+		} `json:"fields"`
+	}
+	if err := c.makeGetRequest(ctx, url, &response); err != nil {
+		return "", fmt.Errorf("error fetching description of issue %s: %w", issueKey, err)
+	}
+
+	return response.Fields.Description, nil
+}
+
+// SetIssueDescription replaces the description of an issue with markdown,
+// sent as an ADF document on API v3
+func (c *Client) SetIssueDescription(ctx context.Context, issueKey string, markdown string) error {
+	payload := map[string]any{
+		"fields": map[string]any{"description": c.richText(markdown)},
+	}
+
+	url := c.apiURL("issue/%s", issueKey)
+	if err := c.makePutRequest(ctx, url, payload, nil); err != nil {
+		return fmt.Errorf("error updating description of issue %s: %w", issueKey, err)
+	}
+
+	return nil
+}
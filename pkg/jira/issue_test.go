@@ -1,6 +1,7 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -42,13 +43,13 @@ func TestGetIssue(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock request function
-			mockRequest := func(url string, target any) error {
+			mockRequest := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
 
 				// Check URL format
-				expectedURL := fmt.Sprintf("%s/rest/api/2/issue/%s", jiraBaseURL, tt.issueKey)
+				expectedURL := fmt.Sprintf("%s/rest/api/2/issue/%s", testBaseURL, tt.issueKey)
 				assert.Equal(t, expectedURL, url)
 
 				// Marshal and unmarshal to simulate JSON response
@@ -57,7 +58,7 @@ func TestGetIssue(t *testing.T) {
 			}
 
 			// Call the function
-			issue, err := GetIssue(tt.issueKey, mockRequest)
+			issue, err := newTestClient(mockRequest).GetIssue(context.Background(), tt.issueKey)
 
 			// Check error
 			if tt.expectedErrMsg != "" {
@@ -73,6 +74,24 @@ func TestGetIssue(t *testing.T) {
 	}
 }
 
+func TestGetIssueResolvesCustomFields(t *testing.T) {
+	client := newTestClient(func(ctx context.Context, url string, target any) error {
+		return json.Unmarshal([]byte(`{"key": "TEST-1", "fields": {
+			"summary": "Estimated",
+			"customfield_12310243": 8,
+			"customfield_12311140": "TEST-100",
+			"customfield_12310940": [{"name": "Sprint 3", "state": "active"}]}}`), target)
+	})
+
+	issue, err := client.GetIssue(context.Background(), "TEST-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 8.0, issue.Fields.StoryPoint)
+	assert.Equal(t, "Sprint 3", issue.Fields.Sprint)
+	if assert.NotNil(t, issue.Fields.Epic) {
+		assert.Equal(t, "TEST-100", issue.Fields.Epic.Key)
+	}
+}
+
 func TestGetValidTransitionID(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -155,9 +174,9 @@ func TestUpdateIssueStatus(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock post request function
-			mockPostRequest := func(url string, payload any, response any) error {
+			mockPostRequest := func(ctx context.Context, url string, payload any, response any) error {
 				// Verify URL format
-				expectedURL := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", jiraBaseURL, tt.issueKey)
+				expectedURL := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", testBaseURL, tt.issueKey)
 				if url != expectedURL {
 					t.Errorf("incorrect URL: got %s, want %s", url, expectedURL)
 				}
@@ -175,7 +194,8 @@ func TestUpdateIssueStatus(t *testing.T) {
 			}
 
 			// Call the function being tested
-			err := UpdateIssueStatus(tt.issueKey, tt.newStatus, mockPostRequest)
+			client := NewClient(testBaseURL, testAuth, WithPostRequestFunc(mockPostRequest))
+			err := client.UpdateIssueStatus(context.Background(), tt.issueKey, tt.newStatus)
 
 			// Check error
 			if (err != nil) != tt.expectedError {
@@ -224,13 +244,13 @@ func TestGetAvailableTransitions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock request function
-			mockRequest := func(url string, target any) error {
+			mockRequest := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
 
 				// Check URL format
-				expectedURL := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", jiraBaseURL, tt.issue.Key)
+				expectedURL := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions?expand=transitions.fields", testBaseURL, tt.issue.Key)
 				if url != expectedURL {
 					t.Errorf("incorrect URL: got %s, want %s", url, expectedURL)
 				}
@@ -241,7 +261,7 @@ func TestGetAvailableTransitions(t *testing.T) {
 			}
 
 			// Call the function
-			transitions, err := GetAvailableTransitions(tt.issue, mockRequest)
+			transitions, err := newTestClient(mockRequest).GetAvailableTransitions(context.Background(), tt.issue)
 
 			// Check error
 			if (err != nil) != tt.expectedError {
@@ -262,3 +282,78 @@ func TestGetAvailableTransitions(t *testing.T) {
 		})
 	}
 }
+
+// testDoneTransition requires a resolution on its screen
+var testDoneTransition = Transition{
+	ID:   "31",
+	Name: "Close",
+	To:   Status{Name: "Done"},
+	Fields: map[string]EditField{
+		"resolution": {Name: "Resolution", Required: true, Schema: FieldSchema{Type: "resolution", System: "resolution"}, Operations: []string{"set"},
+			AllowedValues: []AllowedValue{{ID: "1", Name: "Fixed"}, {ID: "2", Name: "Won't Do"}}},
+		"fixVersions":   {Name: "Fix Version/s", Required: false, Schema: FieldSchema{Type: "array", Items: "version", System: "fixVersions"}, Operations: []string{"set", "add", "remove"}},
+		"customfield_1": {Name: "Sign-off", Required: true, HasDefaultValue: true, Schema: FieldSchema{Type: "string"}},
+	},
+}
+
+func TestFindTransition(t *testing.T) {
+	transitions := []Transition{{ID: "11", Name: "Start", To: Status{Name: "In Progress"}}, testDoneTransition}
+
+	transition, ok := FindTransition("close", transitions)
+	assert.True(t, ok)
+	assert.Equal(t, "31", transition.ID)
+
+	transition, ok = FindTransition("in progress", transitions)
+	assert.True(t, ok, "found by target status")
+	assert.Equal(t, "11", transition.ID)
+
+	_, ok = FindTransition("Reopen", transitions)
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"Resolution"}, testDoneTransition.RequiredFields())
+}
+
+func TestTransitionIssue(t *testing.T) {
+	var payload UpdateTransition
+	posted := false
+	client := NewClient(testBaseURL, testAuth, testServerInfo, testFieldIDs,
+		WithPostRequestFunc(func(ctx context.Context, url string, body any, response any) error {
+			assert.Equal(t, testBaseURL+"/rest/api/2/issue/TEST-1/transitions", url)
+			payload, posted = body.(UpdateTransition), true
+			return nil
+		}))
+
+	err := client.TransitionIssue(context.Background(), "TEST-1", testDoneTransition, TransitionOptions{
+		Resolution: "fixed",
+		Comment:    "Released in **1.2**",
+		Fields:     map[string]any{"Fix Version/s": "1.2"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "31", payload.Transition.ID)
+	assert.Equal(t, map[string]any{
+		"resolution":  map[string]string{"name": "Fixed"},
+		"fixVersions": []any{map[string]string{"name": "1.2"}},
+	}, payload.Fields)
+	// On v2 the Markdown comment is sent as wiki markup
+	assert.Equal(t, map[string]any{
+		"comment": []map[string]any{{"add": map[string]any{"body": "Released in *1.2*"}}},
+	}, payload.Update)
+
+	tests := []struct {
+		name    string
+		options TransitionOptions
+		err     string
+	}{
+		{name: "missing resolution", options: TransitionOptions{Comment: "Done"}, err: "transition Close of issue TEST-1 requires Resolution"},
+		{name: "invalid resolution", options: TransitionOptions{Resolution: "Duplicate"}, err: "Duplicate is not a valid Resolution, expected one of Fixed, Won't Do"},
+		{name: "not on screen", options: TransitionOptions{Resolution: "Fixed", Fields: map[string]any{"Summary": "New"}}, err: "field Summary is not on the screen of transition Close"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posted = false
+			err := client.TransitionIssue(context.Background(), "TEST-1", testDoneTransition, tt.options)
+			assert.ErrorContains(t, err, tt.err)
+			assert.False(t, posted, "nothing is sent")
+		})
+	}
+}
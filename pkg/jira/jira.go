@@ -2,105 +2,290 @@ package jira
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
-
-	"github.com/morfo-si/owlify/pkg/config"
 )
 
-var (
-	jiraBaseURL string
-	jiraToken   string
-	httpProxy   string
-	httpsProxy  string
-)
+// JiraRequestFunc performs a GET request and decodes the response into target
+type JiraRequestFunc func(ctx context.Context, url string, target any) error
+
+// JiraPostRequestFunc sends payload as JSON and decodes the response into target
+type JiraPostRequestFunc func(ctx context.Context, url string, payload any, target any) error
+
+// JiraDeleteRequestFunc performs a DELETE request
+type JiraDeleteRequestFunc func(ctx context.Context, url string) error
+
+// Client talks to a single Jira instance. All API calls are methods on the
+// client so that several instances can be used side by side in one process.
+type Client struct {
+	baseURL     string
+	auth        Authenticator
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	retrier     *retrier
+	rateLimiter *RateLimiter
+	cache       *Cache
+	logger      *slog.Logger
+	apiVersion  APIVersion
+
+	// serverInfo is the result of the serverInfo probe, probeErr its failure
+	probeMu    sync.Mutex
+	serverInfo *ServerInfo
+	probeErr   error
+
+	// fieldIDs override the field registry, which is loaded once from the
+	// field endpoint into fields, fieldsErr its failure
+	fieldsMu  sync.Mutex
+	fieldIDs  map[string]string
+	fields    *FieldRegistry
+	fieldsErr error
+
+	// makeGetRequest, makePostRequest, makePutRequest and makeDeleteRequest
+	// perform the actual HTTP calls. They default to the client's own
+	// JIRAGetRequest, JIRAPostRequest, JIRAPutRequest and JIRADeleteRequest
+	// and can be replaced with WithRequestFunc, WithPostRequestFunc,
+	// WithPutRequestFunc and WithDeleteRequestFunc.
+	makeGetRequest    JiraRequestFunc
+	makePostRequest   JiraPostRequestFunc
+	makePutRequest    JiraPostRequestFunc
+	makeDeleteRequest JiraDeleteRequestFunc
+}
+
+// ClientOption is a function that modifies a Client
+type ClientOption func(*Client)
+
+// NewClient creates a new Client for the Jira instance at baseURL that
+// authenticates requests with auth. A nil auth sends anonymous requests.
+func NewClient(baseURL string, auth Authenticator, options ...ClientOption) *Client {
+	c := &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		auth:        auth,
+		httpClient:  DefaultHTTPClient(),
+		retryPolicy: DefaultRetryPolicy(),
+		logger:      discardLogger,
+		apiVersion:  DefaultAPIVersion,
+	}
+	c.makeGetRequest = c.JIRAGetRequest
+	c.makePostRequest = c.JIRAPostRequest
+	c.makePutRequest = c.JIRAPutRequest
+	c.makeDeleteRequest = c.JIRADeleteRequest
+
+	for _, option := range options {
+		option(c)
+	}
+	c.retrier = newRetrier(c.retryPolicy, c.logger)
+	return c
+}
+
+// WithHTTPClient sets the http.Client used to send requests.
+// Clients should share one http.Client so they share its connection pool.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
 
-func init() {
-	// Initialize configuration
-	if err := config.Initialize(); err != nil {
-		panic(fmt.Sprintf("Failed to initialize configuration: %v", err))
-	}
-	
-	// Get Jira config
-	jiraConfig := config.GetJiraConfig()
-	
-	// Set package variables
-	jiraBaseURL = jiraConfig.BaseURL
-	jiraToken = jiraConfig.Token
-	httpProxy = jiraConfig.HTTPProxy
-	httpsProxy = jiraConfig.HTTPSProxy
-}
-
-// createHTTPClient creates an http.Client with proxy support if configured
-func createHTTPClient() *http.Client {
-	transport := &http.Transport{}
-	if httpProxy != "" || httpsProxy != "" {
-		transport.Proxy = http.ProxyFromEnvironment
-	}
-
-	if transport.Proxy != nil {
-		return &http.Client{
-			Timeout:   10 * time.Second,
-			Transport: transport,
+// WithRequestFunc replaces the function used for GET requests
+func WithRequestFunc(makeGetRequest JiraRequestFunc) ClientOption {
+	return func(c *Client) {
+		c.makeGetRequest = makeGetRequest
+	}
+}
+
+// WithPostRequestFunc replaces the function used for POST requests
+func WithPostRequestFunc(makePostRequest JiraPostRequestFunc) ClientOption {
+	return func(c *Client) {
+		c.makePostRequest = makePostRequest
+	}
+}
+
+// WithPutRequestFunc replaces the function used for PUT requests
+func WithPutRequestFunc(makePutRequest JiraPostRequestFunc) ClientOption {
+	return func(c *Client) {
+		c.makePutRequest = makePutRequest
+	}
+}
+
+// WithDeleteRequestFunc replaces the function used for DELETE requests
+func WithDeleteRequestFunc(makeDeleteRequest JiraDeleteRequestFunc) ClientOption {
+	return func(c *Client) {
+		c.makeDeleteRequest = makeDeleteRequest
+	}
+}
+
+// do sends req, retrying it according to the client's retry policy.
+// Every attempt waits for the rate limiter first; the wait happens outside
+// http.Client.Do so it does not count against the client timeout.
+//
+// The request is logged once the caller closes the response body, so the
+// trace includes the full duration and the number of bytes read.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	trace := &requestTrace{logger: c.logger, req: req, start: time.Now()}
+	resp, err := c.retrier.do(req, func(req *http.Request) (*http.Response, error) {
+		trace.attempts++
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
 		}
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		trace.done(nil, err, 0)
+		return nil, err
 	}
+	resp.Body = &tracedBody{ReadCloser: resp.Body, done: func(bytes int64) { trace.done(resp, nil, bytes) }}
+	return resp, nil
+}
 
-	return &http.Client{
-		Timeout: 10 * time.Second,
+// authenticate adds the client's credentials to req
+func (c *Client) authenticate(req *http.Request) error {
+	if c.auth == nil {
+		return nil
 	}
+	return c.auth.Authenticate(req)
 }
 
-type JiraRequestFunc func(string, any) error
-type JiraPostRequestFunc func(string, any, any) error
+// endpoint returns the path of u relative to the client's base URL, which
+// carries a path prefix of its own when requests go through the OAuth gateway
+func (c *Client) endpoint(u *url.URL) string {
+	if base, err := url.Parse(c.baseURL); err == nil {
+		return strings.TrimPrefix(u.Path, strings.TrimRight(base.Path, "/"))
+	}
+	return u.Path
+}
 
-func JIRAGetRequest(reqUrl string, target any) error {
-	// Replace client creation with new function
-	client := createHTTPClient()
+// BaseURL returns the base URL of the Jira instance
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
 
-	req, err := http.NewRequest("GET", reqUrl, nil)
+func (c *Client) JIRAGetRequest(ctx context.Context, reqUrl string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqUrl, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jiraToken))
+	// Fresh cached responses are served without authenticating or sending anything
+	var cached *cacheEntry
+	var ttl time.Duration
+	if c.cache != nil {
+		ttl = c.cache.ttl(c.endpoint(req.URL))
+		if ttl > 0 {
+			cached = c.cache.lookup(req)
+		}
+		if cached != nil {
+			if cached.fresh(c.cache.now()) {
+				c.logger.LogAttrs(ctx, slog.LevelDebug, "Jira request served from cache",
+					slog.String("method", req.Method),
+					slog.String("url", redactURL(req.URL)),
+					slog.Int("bytes", len(cached.Body)))
+				return json.Unmarshal(cached.Body, target)
+			}
+			cached.setValidators(req)
+		}
+	}
+
+	if err := c.authenticate(req); err != nil {
+		return err
+	}
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
 
-	return json.NewDecoder(resp.Body).Decode(target)
-}
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		// A failed write only costs a future request
+		_ = c.cache.revalidated(req, cached, ttl)
+		return json.Unmarshal(cached.Body, target)
+	}
 
-func JIRAPostRequest(reqUrl string, payload any, target any) error {
-	// Replace client creation with new function
-	client := createHTTPClient()
+	if err := checkResponse(req, resp); err != nil {
+		return err
+	}
+
+	if ttl <= 0 {
+		return json.NewDecoder(resp.Body).Decode(target)
+	}
 
-	jsonPayload, err := json.Marshal(payload)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return err
+	}
+	_ = c.cache.store(req, resp, body, ttl)
+	return nil
+}
+
+func (c *Client) JIRAPostRequest(ctx context.Context, reqUrl string, payload any, target any) error {
+	return c.sendJSON(ctx, "POST", reqUrl, payload, target)
+}
+
+// JIRAPutRequest sends payload as JSON with a PUT request and decodes the
+// response into target unless it is nil
+func (c *Client) JIRAPutRequest(ctx context.Context, reqUrl string, payload any, target any) error {
+	return c.sendJSON(ctx, "PUT", reqUrl, payload, target)
+}
+
+// JIRADeleteRequest sends a DELETE request
+func (c *Client) JIRADeleteRequest(ctx context.Context, reqUrl string) error {
+	return c.sendJSON(ctx, "DELETE", reqUrl, nil, nil)
+}
+
+// sendJSON sends payload as JSON with the given method and decodes the
+// response into target unless it is nil. A nil payload sends no body.
+func (c *Client) sendJSON(ctx context.Context, method, reqUrl string, payload any, target any) error {
+	var body io.Reader
+	if payload != nil {
+		jsonPayload, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(jsonPayload)
+	}
 
-	req, err := http.NewRequest("POST", reqUrl, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl, body)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jiraToken))
+	if err := c.authenticate(req); err != nil {
+		return err
+	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-	resp, err := client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
+
+	if err := checkResponse(req, resp); err != nil {
+		return err
+	}
+
+	// Any change may show up in cached searches, so start over
+	if c.cache != nil {
+		_ = c.cache.invalidate()
+	}
 
-	if target != nil {
+	// Updates answer with 204 No Content
+	if target != nil && resp.StatusCode != http.StatusNoContent {
 		return json.NewDecoder(resp.Body).Decode(target)
 	}
 
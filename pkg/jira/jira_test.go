@@ -1,21 +1,131 @@
 package jira
 
 import (
-	"os"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testBaseURL = "https://example.atlassian.net"
+	testToken   = "mock-token"
 )
 
-func TestMain(m *testing.M) {
-	// Set up mock environment variables for testing
-	os.Setenv("JIRA_BASE_URL", "https://example.atlassian.net")
-	os.Setenv("JIRA_TOKEN", "mock-token")
+var testAuth = BearerAuth{Token: testToken}
+
+// testServerInfo keeps test clients from probing the instance they talk to
+var testServerInfo = WithServerInfo(ServerInfo{DeploymentType: DeploymentServer, VersionNumbers: []int{9, 12, 0}})
+
+// testFieldIDs keeps test clients from requesting the fields of the instance
+var testFieldIDs = WithFieldIDs(map[string]string{
+	FieldStoryPoints:   "customfield_12310243",
+	FieldEpicLink:      "customfield_12311140",
+	FieldSprint:        "customfield_12310940",
+	FieldParentFeature: "customfield_12313140",
+})
+
+// newTestClient creates a Client whose GET requests are served by makeGetRequest
+func newTestClient(makeGetRequest JiraRequestFunc) *Client {
+	return NewClient(testBaseURL, testAuth, WithRequestFunc(makeGetRequest), testServerInfo, testFieldIDs)
+}
+
+func TestNewClient(t *testing.T) {
+	client := NewClient("https://jira.example.com/", testAuth)
+
+	assert.Equal(t, "https://jira.example.com", client.BaseURL())
+	assert.NotNil(t, client.httpClient)
+	assert.NotNil(t, client.makeGetRequest)
+	assert.NotNil(t, client.makePostRequest)
+}
+
+func TestClientsAreIndependent(t *testing.T) {
+	first := NewClient("https://first.example.com", BearerAuth{Token: "first-token"})
+	second := NewClient("https://second.example.com", BasicAuth{Username: "user@example.com", Token: "second-token"})
+
+	assert.Equal(t, "https://first.example.com", first.BaseURL())
+	assert.Equal(t, "https://second.example.com", second.BaseURL())
+	assert.NotEqual(t, first.auth, second.auth)
+}
 
-	// Run the tests
-	exitCode := m.Run()
+func TestJIRAGetRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "Bearer "+testToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		_ = json.NewEncoder(w).Encode(Board{ID: 42, Name: "Board"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()))
+	board, err := client.FetchBoardByID(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, board.ID)
+	assert.Equal(t, "Board", board.Name)
+}
+
+func TestJIRAPostRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/2/issue/TEST-1/transitions", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload UpdateTransition
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		assert.Equal(t, "31", payload.Transition.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()))
+	assert.NoError(t, client.UpdateIssueStatus(context.Background(), "TEST-1", "31"))
+}
+
+func TestJIRAGetRequestCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()))
+	start := time.Now()
+	_, err := client.GetIssue(ctx, "TEST-1")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
 
-	// Clean up
-	os.Unsetenv("JIRA_BASE_URL")
-	os.Unsetenv("JIRA_TOKEN")
+func TestFetchSprintIssuesStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	requests := 0
+	client := newTestClient(func(ctx context.Context, url string, target any) error {
+		requests++
+		if response, ok := target.(*JiraResponse); ok {
+			response.Issues = []Issue{
+				{Key: "TEST-1", Fields: Fields{Epic: &Epic{Key: "EPIC-1"}}},
+				{Key: "TEST-2", Fields: Fields{Epic: &Epic{Key: "EPIC-2"}}},
+			}
+			cancel()
+			return nil
+		}
+		return ctx.Err()
+	})
 
-	os.Exit(exitCode)
+	issues, err := client.FetchSprintIssues(ctx, 1, true)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, issues)
+	assert.Equal(t, 2, requests)
 }
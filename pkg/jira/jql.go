@@ -1,17 +1,70 @@
 package jira
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
-func FetchIssuesFromJQL(jql string, makeGetRequest JiraRequestFunc) ([]Issue, error) {
-	url := fmt.Sprintf("%s/rest/api/2/search?jql=%s", jiraBaseURL, url.QueryEscape(jql))
+// FetchIssuesFromJQL retrieves the issues matching a JQL query, following
+// pagination until every issue has been fetched or limit is reached.
+// A limit of zero or less fetches all matching issues.
+func (c *Client) FetchIssuesFromJQL(ctx context.Context, jql string, limit int) ([]Issue, error) {
+	return collect(c.IssuesFromJQL(ctx, jql), limit)
+}
 
-	var jiraResponse JiraResponse
-	if err := makeGetRequest(url, &jiraResponse); err != nil {
-		return nil, err
+// IssuesFromJQL returns an iterator over all issues matching a JQL query.
+// Pages are requested from Jira as the iterator advances. Jira Cloud is
+// searched through search/jql, which replaces the search endpoint there.
+func (c *Client) IssuesFromJQL(ctx context.Context, jql string) iter.Seq2[Issue, error] {
+	return func(yield func(Issue, error) bool) {
+		issues := c.searchIssues(ctx, jql)
+		if c.isCloud(ctx) {
+			issues = c.searchIssuesByToken(ctx, jql)
+		}
+		for issue, err := range issues {
+			if !yield(issue, err) {
+				return
+			}
+		}
 	}
+}
+
+// searchIssues pages through the search endpoint of Server and Data Center
+func (c *Client) searchIssues(ctx context.Context, jql string) iter.Seq2[Issue, error] {
+	return paginate(0, func(startAt int) ([]Issue, pageInfo, error) {
+		searchURL := c.apiURL("search?jql=%s", url.QueryEscape(jql))
+		if startAt > 0 {
+			searchURL = fmt.Sprintf("%s&startAt=%d", searchURL, startAt)
+		}
+
+		var jiraResponse JiraResponse
+		if err := c.makeGetRequest(ctx, searchURL, &jiraResponse); err != nil {
+			return nil, pageInfo{}, err
+		}
+		c.resolveFields(ctx, jiraResponse.Issues)
+		return jiraResponse.Issues, jiraResponse.pageInfo(), nil
+	})
+}
+
+// searchIssuesByToken pages through the search/jql endpoint of Jira Cloud.
+// It only returns issue IDs unless fields are requested explicitly.
+func (c *Client) searchIssuesByToken(ctx context.Context, jql string) iter.Seq2[Issue, error] {
+	return paginateTokens(func(token string) ([]Issue, string, error) {
+		searchURL := c.apiURL("search/jql?jql=%s&fields=*navigable", url.QueryEscape(jql))
+		if token != "" {
+			searchURL = fmt.Sprintf("%s&nextPageToken=%s", searchURL, url.QueryEscape(token))
+		}
 
-	return jiraResponse.Issues, nil
+		var jiraResponse JiraResponse
+		if err := c.makeGetRequest(ctx, searchURL, &jiraResponse); err != nil {
+			return nil, "", err
+		}
+		c.resolveFields(ctx, jiraResponse.Issues)
+		if jiraResponse.IsLast {
+			return jiraResponse.Issues, "", nil
+		}
+		return jiraResponse.Issues, jiraResponse.NextPageToken, nil
+	})
 }
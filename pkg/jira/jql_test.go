@@ -1,6 +1,7 @@
 package jira
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -9,7 +10,7 @@ import (
 )
 
 // Mock function for JIRAGetRequest
-func mockJIRAGetRequest(url string, response any) error {
+func mockJIRAGetRequest(ctx context.Context, url string, response any) error {
 	if url == "error" {
 		return errors.New("failed to fetch issues")
 	}
@@ -64,7 +65,7 @@ func mockJIRAGetRequest(url string, response any) error {
 func TestFetchIssuesFromJQL_Success(t *testing.T) {
 	jql := "project=TEST"
 
-	issues, err := FetchIssuesFromJQL(jql, mockJIRAGetRequest)
+	issues, err := newTestClient(mockJIRAGetRequest).FetchIssuesFromJQL(context.Background(), jql, 0)
 	assert.NoError(t, err)
 	assert.Len(t, issues, 2)
 	assert.Equal(t, "ISSUE-1", issues[0].Key)
@@ -84,18 +85,18 @@ func TestFetchIssuesFromJQL_APIError(t *testing.T) {
 	jql := "error"
 
 	// Mock function that returns an error
-	mockErrorFunc := func(url string, response any) error {
+	mockErrorFunc := func(ctx context.Context, url string, response any) error {
 		return errors.New("API failure")
 	}
 
-	issues, err := FetchIssuesFromJQL(jql, mockErrorFunc)
+	issues, err := newTestClient(mockErrorFunc).FetchIssuesFromJQL(context.Background(), jql, 0)
 	assert.Error(t, err)
 	assert.Nil(t, issues)
 }
 
 func TestFetchIssuesFromJQL_WithOverdueIssue(t *testing.T) {
 	// Create a mock function that returns an overdue issue
-	mockOverdueFunc := func(url string, response any) error {
+	mockOverdueFunc := func(ctx context.Context, url string, response any) error {
 		// Create a date in the past
 		pastDate := time.Now().AddDate(0, 0, -1) // Yesterday
 
@@ -120,7 +121,7 @@ func TestFetchIssuesFromJQL_WithOverdueIssue(t *testing.T) {
 	}
 
 	jql := "project=TEST"
-	issues, err := FetchIssuesFromJQL(jql, mockOverdueFunc)
+	issues, err := newTestClient(mockOverdueFunc).FetchIssuesFromJQL(context.Background(), jql, 0)
 
 	assert.NoError(t, err)
 	assert.Len(t, issues, 1)
@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithLogger sets the logger Jira requests are traced to. Every request is
+// logged at debug level once its response has been read, and retries are
+// logged at info level. Credentials are always redacted.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// discardLogger is used when no logger is configured
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// requestTrace collects what is logged about a single request
+type requestTrace struct {
+	logger   *slog.Logger
+	req      *http.Request
+	start    time.Time
+	attempts int
+}
+
+// done logs the outcome of the request
+func (t *requestTrace) done(resp *http.Response, err error, bytes int64) {
+	ctx := t.req.Context()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", t.req.Method),
+		slog.String("url", redactURL(t.req.URL)),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	attrs = append(attrs,
+		slog.Duration("duration", time.Since(t.start)),
+		slog.Int64("bytes", bytes),
+		slog.Int("retries", max(t.attempts-1, 0)),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	attrs = append(attrs, headerAttr("request_headers", t.req.Header))
+	if resp != nil {
+		attrs = append(attrs, headerAttr("response_headers", resp.Header))
+	}
+	t.logger.LogAttrs(ctx, slog.LevelDebug, "Jira request", attrs...)
+}
+
+// headerAttr returns header as a log group with credentials redacted
+func headerAttr(name string, header http.Header) slog.Attr {
+	header = redactHeader(header)
+	attrs := make([]any, 0, len(header))
+	for _, key := range slices.Sorted(maps.Keys(header)) {
+		attrs = append(attrs, slog.String(key, header.Get(key)))
+	}
+	return slog.Group(name, attrs...)
+}
+
+// tracedBody counts the bytes read from a response body and reports them
+// when the body is closed
+type tracedBody struct {
+	io.ReadCloser
+	bytes int64
+	once  sync.Once
+	done  func(bytes int64)
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.bytes) })
+	return err
+}
+
+// logRetry records that an attempt failed and is about to be retried
+func logRetry(ctx context.Context, logger *slog.Logger, req *http.Request, resp *http.Response, err error, attempt int, wait time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Int("attempt", attempt),
+		slog.Duration("wait", wait),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, "Retrying Jira request", attrs...)
+}
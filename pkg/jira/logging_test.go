@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger returns a JSON logger writing debug records to buf
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// logRecords decodes the JSON log lines in buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestClientLogsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"TEST-1"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), WithLogger(newTestLogger(&buf)))
+	err := client.JIRAGetRequest(context.Background(), server.URL+"/rest/api/2/issue/TEST-1?access_token=secret", &Issue{})
+	require.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), testToken)
+	assert.NotContains(t, buf.String(), "secret")
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "DEBUG", record["level"])
+	assert.Equal(t, "Jira request", record["msg"])
+	assert.Equal(t, "GET", record["method"])
+	assert.Equal(t, server.URL+"/rest/api/2/issue/TEST-1?access_token=REDACTED", record["url"])
+	assert.Equal(t, float64(http.StatusOK), record["status"])
+	assert.Equal(t, float64(len(`{"key":"TEST-1"}`)), record["bytes"])
+	assert.Equal(t, float64(0), record["retries"])
+	assert.Contains(t, record, "duration")
+	assert.Equal(t, redacted, record["request_headers"].(map[string]any)["Authorization"])
+}
+
+func TestClientLogsRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"key":"TEST-1"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(server.URL, testAuth,
+		WithHTTPClient(server.Client()),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2}),
+		WithLogger(newTestLogger(&buf)))
+	_, err := client.GetIssue(context.Background(), "TEST-1")
+	require.NoError(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, "Retrying Jira request", records[0]["msg"])
+	assert.Equal(t, float64(http.StatusServiceUnavailable), records[0]["status"])
+	assert.Equal(t, float64(1), records[0]["attempt"])
+	assert.Equal(t, float64(1), records[1]["retries"])
+}
+
+func TestClientLogsFailedRequests(t *testing.T) {
+	var buf bytes.Buffer
+	client := NewClient("http://127.0.0.1:1", testAuth,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}),
+		WithLogger(newTestLogger(&buf)))
+	_, err := client.GetIssue(context.Background(), "TEST-1")
+	require.Error(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Contains(t, records[0]["error"], "connect")
+	assert.NotContains(t, records[0], "status")
+}
+
+func TestClientWithoutLoggerIsSilent(t *testing.T) {
+	client := NewClient(testBaseURL, testAuth)
+	assert.False(t, client.logger.Enabled(context.Background(), slog.LevelError))
+}
@@ -0,0 +1,315 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Atlassian OAuth 2.0 (3LO) endpoints
+const (
+	AtlassianAuthURL      = "https://auth.atlassian.com/authorize"
+	AtlassianTokenURL     = "https://auth.atlassian.com/oauth/token"
+	AtlassianResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	AtlassianAPIURL       = "https://api.atlassian.com/ex/jira"
+)
+
+// tokenExpiryDelta refreshes tokens slightly before they expire
+const tokenExpiryDelta = time.Minute
+
+// OAuthConfig describes an OAuth 2.0 authorization code (3LO) application
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// AuthURL, TokenURL and ResourcesURL default to the Atlassian endpoints
+	AuthURL      string
+	TokenURL     string
+	ResourcesURL string
+
+	// HTTPClient is used for token requests; nil uses a client with a 10s timeout
+	HTTPClient *http.Client
+}
+
+// OAuthToken holds the tokens issued for a user together with the Jira
+// site they grant access to
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+	CloudID      string    `json:"cloud_id,omitempty"`
+	SiteURL      string    `json:"site_url,omitempty"`
+}
+
+// Valid reports whether the access token is present and not about to expire
+func (t *OAuthToken) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(t.Expiry)
+}
+
+// APIBaseURL returns the base URL for REST calls made with this token
+func (t *OAuthToken) APIBaseURL() string {
+	return fmt.Sprintf("%s/%s", AtlassianAPIURL, t.CloudID)
+}
+
+// AccessibleResource is a Jira site an OAuth token grants access to
+type AccessibleResource struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+func (c OAuthConfig) authURL() string {
+	if c.AuthURL != "" {
+		return c.AuthURL
+	}
+	return AtlassianAuthURL
+}
+
+func (c OAuthConfig) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	return AtlassianTokenURL
+}
+
+func (c OAuthConfig) resourcesURL() string {
+	if c.ResourcesURL != "" {
+		return c.ResourcesURL
+	}
+	return AtlassianResourcesURL
+}
+
+func (c OAuthConfig) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// AuthCodeURL returns the URL the user visits to grant access
+func (c OAuthConfig) AuthCodeURL(state string) string {
+	params := url.Values{}
+	params.Set("audience", "api.atlassian.com")
+	params.Set("client_id", c.ClientID)
+	params.Set("scope", strings.Join(c.Scopes, " "))
+	params.Set("redirect_uri", c.RedirectURL)
+	params.Set("state", state)
+	params.Set("response_type", "code")
+	params.Set("prompt", "consent")
+	return fmt.Sprintf("%s?%s", c.authURL(), params.Encode())
+}
+
+// Exchange trades an authorization code for tokens
+func (c OAuthConfig) Exchange(ctx context.Context, code string) (*OAuthToken, error) {
+	params := url.Values{}
+	params.Set("grant_type", "authorization_code")
+	params.Set("code", code)
+	params.Set("redirect_uri", c.RedirectURL)
+	return c.requestToken(ctx, params)
+}
+
+// Refresh obtains a new access token using a refresh token
+func (c OAuthConfig) Refresh(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	params := url.Values{}
+	params.Set("grant_type", "refresh_token")
+	params.Set("refresh_token", refreshToken)
+	return c.requestToken(ctx, params)
+}
+
+// requestToken posts a token request and decodes the response
+func (c OAuthConfig) requestToken(ctx context.Context, params url.Values) (*OAuthToken, error) {
+	params.Set("client_id", c.ClientID)
+	params.Set("client_secret", c.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.tokenURL(), strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&oauthErr)
+		if oauthErr.Error != "" {
+			return nil, fmt.Errorf("token request failed: %s: %s", oauthErr.Error, oauthErr.Description)
+		}
+		return nil, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, fmt.Errorf("error decoding token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("token response did not include an access token")
+	}
+
+	token := &OAuthToken{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    tokenResp.TokenType,
+	}
+	if tokenResp.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// AccessibleResources lists the Jira sites the token grants access to
+func (c OAuthConfig) AccessibleResources(ctx context.Context, token *OAuthToken) ([]AccessibleResource, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.resourcesURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := (BearerAuth{Token: token.AccessToken}).Authenticate(req); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(req, resp); err != nil {
+		return nil, err
+	}
+
+	var resources []AccessibleResource
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return nil, fmt.Errorf("error decoding accessible resources: %w", err)
+	}
+	return resources, nil
+}
+
+// TokenStore persists OAuth tokens between runs
+type TokenStore interface {
+	LoadToken() (*OAuthToken, error)
+	SaveToken(token *OAuthToken) error
+}
+
+// FileTokenStore stores a token as JSON in a file readable only by its owner
+type FileTokenStore struct {
+	Path string
+}
+
+// LoadToken implements TokenStore
+func (s FileTokenStore) LoadToken() (*OAuthToken, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no OAuth token found at %s; run 'owlify auth login' first", s.Path)
+		}
+		return nil, err
+	}
+
+	var token OAuthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("error reading OAuth token from %s: %w", s.Path, err)
+	}
+	return &token, nil
+}
+
+// SaveToken implements TokenStore
+func (s FileTokenStore) SaveToken(token *OAuthToken) error {
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+// OAuthAuth authenticates with an OAuth 2.0 access token, transparently
+// refreshing and persisting it when it is about to expire
+type OAuthAuth struct {
+	config OAuthConfig
+	store  TokenStore
+
+	mu    sync.Mutex
+	token *OAuthToken
+}
+
+// NewOAuthAuth creates an OAuthAuth that loads its token from store
+func NewOAuthAuth(config OAuthConfig, store TokenStore) *OAuthAuth {
+	return &OAuthAuth{config: config, store: store}
+}
+
+// Token returns a valid token, loading or refreshing it as needed
+func (a *OAuthAuth) Token(ctx context.Context) (*OAuthToken, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		token, err := a.store.LoadToken()
+		if err != nil {
+			return nil, err
+		}
+		a.token = token
+	}
+	if a.token.Valid() {
+		return a.token, nil
+	}
+
+	if a.token.RefreshToken == "" {
+		return nil, fmt.Errorf("OAuth token expired; run 'owlify auth login' again")
+	}
+	refreshed, err := a.config.Refresh(ctx, a.token.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing OAuth token: %w", err)
+	}
+
+	// Keep the site and the old refresh token unless a rotated one was issued
+	refreshed.CloudID = a.token.CloudID
+	refreshed.SiteURL = a.token.SiteURL
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = a.token.RefreshToken
+	}
+	if err := a.store.SaveToken(refreshed); err != nil {
+		return nil, fmt.Errorf("error saving refreshed OAuth token: %w", err)
+	}
+	a.token = refreshed
+	return a.token, nil
+}
+
+// Authenticate implements Authenticator
+func (a *OAuthAuth) Authenticate(req *http.Request) error {
+	token, err := a.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	return BearerAuth{Token: token.AccessToken}.Authenticate(req)
+}
@@ -0,0 +1,138 @@
+package jira
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// callbackResult carries the outcome of the OAuth redirect to the loopback server
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Login runs the authorization code flow. It starts a loopback server on
+// the configured redirect URL, passes the consent page URL to openURL and
+// exchanges the code Jira redirects back with for tokens.
+func (c OAuthConfig) Login(ctx context.Context, openURL func(string) error) (*OAuthToken, error) {
+	redirect, err := url.Parse(c.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL %q: %w", c.RedirectURL, err)
+	}
+	if redirect.Scheme != "http" || !isLoopbackHost(redirect.Hostname()) {
+		return nil, fmt.Errorf("redirect URL %q must be an http URL on localhost", c.RedirectURL)
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("error starting callback server on %s: %w", redirect.Host, err)
+	}
+
+	results := make(chan callbackResult, 1)
+	path := redirect.Path
+	if path == "" {
+		path = "/"
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		// Requests without our state, such as prefetches or forged
+		// callbacks, are refused without ending the login
+		if r.URL.Query().Get("state") != state {
+			http.Error(w, "Authentication failed: state mismatch in OAuth callback", http.StatusBadRequest)
+			return
+		}
+
+		result := parseCallback(r.URL.Query())
+		if result.err != nil {
+			http.Error(w, fmt.Sprintf("Authentication failed: %v", result.err), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Authentication complete. You can close this window and return to owlify.")
+		}
+
+		// Only the first valid callback counts
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	if err := openURL(c.AuthCodeURL(state)); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if result.err != nil {
+			return nil, result.err
+		}
+		return c.Exchange(ctx, result.code)
+	}
+}
+
+// parseCallback extracts the authorization code from the query of a
+// callback whose state was checked
+func parseCallback(query url.Values) callbackResult {
+	if errCode := query.Get("error"); errCode != "" {
+		return callbackResult{err: fmt.Errorf("authorization denied: %s: %s", errCode, query.Get("error_description"))}
+	}
+	code := query.Get("code")
+	if code == "" {
+		return callbackResult{err: fmt.Errorf("OAuth callback did not include an authorization code")}
+	}
+	return callbackResult{code: code}
+}
+
+// FindResource returns the accessible resource for siteURL, or the only
+// resource if siteURL is empty and the token grants access to a single site
+func FindResource(resources []AccessibleResource, siteURL string) (AccessibleResource, error) {
+	if siteURL == "" {
+		if len(resources) == 1 {
+			return resources[0], nil
+		}
+		return AccessibleResource{}, fmt.Errorf("token grants access to %d sites; set JIRA_BASE_URL to choose one", len(resources))
+	}
+
+	want := strings.TrimRight(siteURL, "/")
+	for _, resource := range resources {
+		if strings.EqualFold(strings.TrimRight(resource.URL, "/"), want) {
+			return resource, nil
+		}
+	}
+	return AccessibleResource{}, fmt.Errorf("token does not grant access to %s", siteURL)
+}
+
+// isLoopbackHost reports whether host refers to the local machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// randomState returns an unguessable value for the OAuth state parameter
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating OAuth state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
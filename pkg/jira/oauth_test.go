@@ -0,0 +1,292 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuthServer is a minimal OAuth 2.0 authorization server
+type fakeAuthServer struct {
+	*httptest.Server
+	t             *testing.T
+	code          string
+	refreshCount  int
+	issuedRefresh string
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	s := &fakeAuthServer{t: t, code: "auth-code", issuedRefresh: "refresh-1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		assert.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != s.code {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Invalid authorization code"}`))
+				return
+			}
+			writeTokenResponse(w, "access-0", s.issuedRefresh, 3600)
+		case "refresh_token":
+			assert.Equal(t, s.issuedRefresh, r.PostForm.Get("refresh_token"))
+			s.refreshCount++
+			s.issuedRefresh = fmt.Sprintf("refresh-%d", s.refreshCount+1)
+			writeTokenResponse(w, fmt.Sprintf("access-%d", s.refreshCount), s.issuedRefresh, 3600)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	mux.HandleFunc("/oauth/token/accessible-resources", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer access-0", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"id":"cloud-1","url":"https://example.atlassian.net","name":"example"}]`))
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func writeTokenResponse(w http.ResponseWriter, access, refresh string, expiresIn int) {
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token":  access,
+		"refresh_token": refresh,
+		"token_type":    "Bearer",
+		"expires_in":    expiresIn,
+	})
+}
+
+func (s *fakeAuthServer) config(redirectURL string) OAuthConfig {
+	return OAuthConfig{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"read:jira-work", "offline_access"},
+		AuthURL:      s.URL + "/authorize",
+		TokenURL:     s.URL + "/oauth/token",
+		ResourcesURL: s.URL + "/oauth/token/accessible-resources",
+		HTTPClient:   s.Client(),
+	}
+}
+
+// freeRedirectURL returns a loopback redirect URL on an unused port
+func freeRedirectURL(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return fmt.Sprintf("http://%s/callback", addr)
+}
+
+// memoryTokenStore keeps a token in memory
+type memoryTokenStore struct {
+	token *OAuthToken
+	saves int
+}
+
+func (s *memoryTokenStore) LoadToken() (*OAuthToken, error) {
+	if s.token == nil {
+		return nil, fmt.Errorf("no token")
+	}
+	copied := *s.token
+	return &copied, nil
+}
+
+func (s *memoryTokenStore) SaveToken(token *OAuthToken) error {
+	copied := *token
+	s.token = &copied
+	s.saves++
+	return nil
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	cfg := OAuthConfig{ClientID: "client-id", RedirectURL: "http://localhost:8765/callback", Scopes: []string{"read:jira-work", "offline_access"}}
+
+	parsed, err := url.Parse(cfg.AuthCodeURL("xyz"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "auth.atlassian.com", parsed.Host)
+	query := parsed.Query()
+	assert.Equal(t, "api.atlassian.com", query.Get("audience"))
+	assert.Equal(t, "client-id", query.Get("client_id"))
+	assert.Equal(t, "read:jira-work offline_access", query.Get("scope"))
+	assert.Equal(t, "http://localhost:8765/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "xyz", query.Get("state"))
+	assert.Equal(t, "code", query.Get("response_type"))
+}
+
+func TestLogin(t *testing.T) {
+	server := newFakeAuthServer(t)
+	defer server.Close()
+	cfg := server.config(freeRedirectURL(t))
+
+	// Play the browser: approve the consent page by following the redirect
+	browser := func(authURL string) error {
+		parsed, err := url.Parse(authURL)
+		require.NoError(t, err)
+		callback := fmt.Sprintf("%s?code=%s&state=%s", parsed.Query().Get("redirect_uri"), server.code, parsed.Query().Get("state"))
+		go func() {
+			resp, err := http.Get(callback)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := cfg.Login(ctx, browser)
+	require.NoError(t, err)
+	assert.Equal(t, "access-0", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+	assert.True(t, token.Valid())
+
+	resources, err := cfg.AccessibleResources(ctx, token)
+	require.NoError(t, err)
+	resource, err := FindResource(resources, "https://example.atlassian.net/")
+	require.NoError(t, err)
+	assert.Equal(t, "cloud-1", resource.ID)
+}
+
+func TestLogin_IgnoresForgedCallback(t *testing.T) {
+	server := newFakeAuthServer(t)
+	defer server.Close()
+	cfg := server.config(freeRedirectURL(t))
+
+	// A forged callback is refused, and the login still completes with the
+	// real one that follows
+	browser := func(authURL string) error {
+		parsed, _ := url.Parse(authURL)
+		redirect := parsed.Query().Get("redirect_uri")
+		go func() {
+			resp, err := http.Get(fmt.Sprintf("%s?code=%s&state=forged", redirect, server.code))
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			}
+			resp, err = http.Get(fmt.Sprintf("%s?code=%s&state=%s", redirect, server.code, parsed.Query().Get("state")))
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			}
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := cfg.Login(ctx, browser)
+	require.NoError(t, err)
+	assert.Equal(t, "access-0", token.AccessToken)
+}
+
+func TestLogin_RequiresLoopbackRedirect(t *testing.T) {
+	cfg := OAuthConfig{RedirectURL: "https://example.com/callback"}
+	_, err := cfg.Login(context.Background(), func(string) error { return nil })
+	assert.ErrorContains(t, err, "must be an http URL on localhost")
+}
+
+func TestExchange_InvalidCode(t *testing.T) {
+	server := newFakeAuthServer(t)
+	defer server.Close()
+
+	_, err := server.config("http://localhost/callback").Exchange(context.Background(), "wrong")
+	assert.EqualError(t, err, "token request failed: invalid_grant: Invalid authorization code")
+}
+
+func TestOAuthAuth_RefreshesExpiredToken(t *testing.T) {
+	server := newFakeAuthServer(t)
+	defer server.Close()
+
+	store := &memoryTokenStore{token: &OAuthToken{
+		AccessToken:  "expired",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Hour),
+		CloudID:      "cloud-1",
+	}}
+	auth := NewOAuthAuth(server.config("http://localhost/callback"), store)
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"key":"TEST-1"}`))
+	}))
+	defer api.Close()
+
+	client := NewClient(api.URL, auth, WithHTTPClient(api.Client()))
+	for i := 0; i < 2; i++ {
+		_, err := client.GetIssue(context.Background(), "TEST-1")
+		require.NoError(t, err)
+	}
+
+	// The token was refreshed once and the rotated refresh token persisted
+	assert.Equal(t, 1, server.refreshCount)
+	assert.Equal(t, 1, store.saves)
+	assert.Equal(t, "access-1", store.token.AccessToken)
+	assert.Equal(t, "refresh-2", store.token.RefreshToken)
+	assert.Equal(t, "cloud-1", store.token.CloudID)
+}
+
+func TestOAuthAuth_ExpiredWithoutRefreshToken(t *testing.T) {
+	store := &memoryTokenStore{token: &OAuthToken{AccessToken: "expired", Expiry: time.Now().Add(-time.Hour)}}
+	auth := NewOAuthAuth(OAuthConfig{}, store)
+
+	_, err := auth.Token(context.Background())
+	assert.ErrorContains(t, err, "owlify auth login")
+}
+
+func TestFileTokenStore(t *testing.T) {
+	store := FileTokenStore{Path: filepath.Join(t.TempDir(), "owlify", "oauth-token.json")}
+
+	_, err := store.LoadToken()
+	assert.ErrorContains(t, err, "owlify auth login")
+
+	token := &OAuthToken{AccessToken: "access", RefreshToken: "refresh", CloudID: "cloud-1"}
+	require.NoError(t, store.SaveToken(token))
+
+	info, err := os.Stat(store.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := store.LoadToken()
+	require.NoError(t, err)
+	assert.Equal(t, token.AccessToken, loaded.AccessToken)
+	assert.Equal(t, token.CloudID, loaded.CloudID)
+	assert.Equal(t, AtlassianAPIURL+"/cloud-1", loaded.APIBaseURL())
+}
+
+func TestFindResource(t *testing.T) {
+	resources := []AccessibleResource{
+		{ID: "1", URL: "https://one.atlassian.net"},
+		{ID: "2", URL: "https://two.atlassian.net"},
+	}
+
+	resource, err := FindResource(resources, "https://TWO.atlassian.net/")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", resource.ID)
+
+	_, err = FindResource(resources, "https://three.atlassian.net")
+	assert.Error(t, err)
+
+	_, err = FindResource(resources, "")
+	assert.Error(t, err)
+
+	resource, err = FindResource(resources[:1], "")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", resource.ID)
+}
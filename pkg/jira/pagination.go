@@ -0,0 +1,123 @@
+package jira
+
+import (
+	"iter"
+)
+
+// pageInfo holds the pagination fields Jira returns with paged responses
+type pageInfo struct {
+	StartAt    int
+	MaxResults int
+	Total      int
+	IsLast     bool
+}
+
+// isLastPage reports whether a page holding count values is the final one.
+// Responses without any pagination metadata are treated as a single page.
+func (p pageInfo) isLastPage(count int) bool {
+	if count == 0 || p.IsLast {
+		return true
+	}
+	if p.Total > 0 {
+		return p.StartAt+count >= p.Total
+	}
+	return p.MaxResults == 0
+}
+
+// pageFetcher fetches the page of values starting at the given index
+type pageFetcher[T any] func(startAt int) ([]T, pageInfo, error)
+
+// paginate returns an iterator over every value of a paginated endpoint.
+// Pages are fetched lazily, following startAt until Jira reports the last
+// page, so breaking out of the loop stops any further requests.
+func paginate[T any](startAt int, fetchPage pageFetcher[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			values, page, err := fetchPage(startAt)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, value := range values {
+				if !yield(value, nil) {
+					return
+				}
+			}
+
+			if page.isLastPage(len(values)) {
+				return
+			}
+			startAt += len(values)
+		}
+	}
+}
+
+// tokenPageFetcher fetches the page following token, an empty token
+// fetching the first page. It returns the token of the next page, which is
+// empty after the last page.
+type tokenPageFetcher[T any] func(token string) ([]T, string, error)
+
+// paginateTokens returns an iterator over every value of an endpoint paged
+// by continuation tokens instead of startAt, such as Cloud's search/jql
+func paginateTokens[T any](fetchPage tokenPageFetcher[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		token := ""
+		for {
+			values, next, err := fetchPage(token)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, value := range values {
+				if !yield(value, nil) {
+					return
+				}
+			}
+
+			if next == "" || len(values) == 0 {
+				return
+			}
+			token = next
+		}
+	}
+}
+
+// collect gathers the values of an iterator into a slice.
+// A limit of zero or less collects every value.
+func collect[T any](values iter.Seq2[T, error], limit int) ([]T, error) {
+	var result []T
+	for value, err := range values {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (r JiraResponse) pageInfo() pageInfo {
+	return pageInfo{StartAt: r.StartAt, MaxResults: r.MaxResults, Total: r.Total}
+}
+
+func (r BoardResponse) pageInfo() pageInfo {
+	return pageInfo{StartAt: r.StartAt, MaxResults: r.MaxResults, Total: r.Total, IsLast: r.IsLast}
+}
+
+func (r SprintResponse) pageInfo() pageInfo {
+	return pageInfo{StartAt: r.StartAt, MaxResults: r.MaxResults, IsLast: r.IsLast}
+}
+
+func (r CommentResponse) pageInfo() pageInfo {
+	return pageInfo{StartAt: r.StartAt, MaxResults: r.MaxResults, Total: r.Total}
+}
+
+func (r WorklogResponse) pageInfo() pageInfo {
+	return pageInfo{StartAt: r.StartAt, MaxResults: r.MaxResults, Total: r.Total}
+}
@@ -0,0 +1,151 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedSearchRequest serves total issues from the search endpoint in pages of pageSize
+func pagedSearchRequest(t *testing.T, total, pageSize int, requests *int) JiraRequestFunc {
+	return func(ctx context.Context, reqURL string, target any) error {
+		*requests++
+
+		parsed, err := url.Parse(reqURL)
+		assert.NoError(t, err)
+		startAt, _ := strconv.Atoi(parsed.Query().Get("startAt"))
+
+		response := target.(*JiraResponse)
+		response.StartAt = startAt
+		response.MaxResults = pageSize
+		response.Total = total
+		for i := startAt; i < total && i < startAt+pageSize; i++ {
+			response.Issues = append(response.Issues, Issue{Key: fmt.Sprintf("TEST-%d", i+1)})
+		}
+		return nil
+	}
+}
+
+func TestFetchIssuesFromJQL_Pagination(t *testing.T) {
+	tests := []struct {
+		name             string
+		total            int
+		limit            int
+		expectedIssues   int
+		expectedRequests int
+	}{
+		{name: "single page", total: 20, limit: 0, expectedIssues: 20, expectedRequests: 1},
+		{name: "multiple pages", total: 120, limit: 0, expectedIssues: 120, expectedRequests: 3},
+		{name: "exact page boundary", total: 100, limit: 0, expectedIssues: 100, expectedRequests: 2},
+		{name: "limit within first page", total: 120, limit: 10, expectedIssues: 10, expectedRequests: 1},
+		{name: "limit across pages", total: 120, limit: 60, expectedIssues: 60, expectedRequests: 2},
+		{name: "limit above total", total: 30, limit: 100, expectedIssues: 30, expectedRequests: 1},
+		{name: "no results", total: 0, limit: 0, expectedIssues: 0, expectedRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			client := newTestClient(pagedSearchRequest(t, tt.total, 50, &requests))
+
+			issues, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", tt.limit)
+			assert.NoError(t, err)
+			assert.Len(t, issues, tt.expectedIssues)
+			assert.Equal(t, tt.expectedRequests, requests)
+			if tt.expectedIssues > 0 {
+				assert.Equal(t, "TEST-1", issues[0].Key)
+				assert.Equal(t, fmt.Sprintf("TEST-%d", tt.expectedIssues), issues[len(issues)-1].Key)
+			}
+		})
+	}
+}
+
+func TestFetchIssuesFromJQL_ErrorOnLaterPage(t *testing.T) {
+	requests := 0
+	paged := pagedSearchRequest(t, 120, 50, &requests)
+	client := newTestClient(func(ctx context.Context, reqURL string, target any) error {
+		if requests == 1 {
+			return errors.New("API failure")
+		}
+		return paged(ctx, reqURL, target)
+	})
+
+	issues, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", 0)
+	assert.EqualError(t, err, "API failure")
+	assert.Nil(t, issues)
+}
+
+func TestFetchBoards_IsLast(t *testing.T) {
+	var urls []string
+	client := newTestClient(func(ctx context.Context, reqURL string, target any) error {
+		urls = append(urls, reqURL)
+		response := target.(*BoardResponse)
+		if len(urls) == 1 {
+			*response = BoardResponse{MaxResults: 2, Values: []Board{{ID: 1}, {ID: 2}}}
+			return nil
+		}
+		*response = BoardResponse{StartAt: 2, MaxResults: 2, IsLast: true, Values: []Board{{ID: 3}}}
+		return nil
+	})
+
+	boards, err := client.FetchBoards(context.Background(), "TEST", 0)
+	assert.NoError(t, err)
+	assert.Len(t, boards, 3)
+	assert.Equal(t, []string{
+		testBaseURL + "/rest/agile/1.0/board?projectKeyOrId=TEST",
+		testBaseURL + "/rest/agile/1.0/board?projectKeyOrId=TEST&startAt=2",
+	}, urls)
+}
+
+func TestFetchSprints_Pagination(t *testing.T) {
+	var urls []string
+	client := newTestClient(func(ctx context.Context, reqURL string, target any) error {
+		urls = append(urls, reqURL)
+		response := target.(*SprintResponse)
+		switch len(urls) {
+		case 1:
+			*response = SprintResponse{MaxResults: 2, Values: []Sprint{{ID: 1}, {ID: 2}}}
+		case 2:
+			*response = SprintResponse{StartAt: 2, MaxResults: 2, Values: []Sprint{{ID: 3}, {ID: 4}}}
+		default:
+			*response = SprintResponse{StartAt: 4, MaxResults: 2, IsLast: true, Values: []Sprint{{ID: 5}}}
+		}
+		return nil
+	})
+
+	sprints, err := client.FetchSprints(context.Background(), 7, WithSprintState(SprintStateClosed), WithLimit(3))
+	assert.NoError(t, err)
+	assert.Len(t, sprints, 3)
+	assert.Equal(t, 3, sprints[2].ID)
+	assert.Equal(t, []string{
+		testBaseURL + "/rest/agile/1.0/board/7/sprint?state=closed",
+		testBaseURL + "/rest/agile/1.0/board/7/sprint?startAt=2&state=closed",
+	}, urls)
+}
+
+func TestIsLastPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     pageInfo
+		count    int
+		expected bool
+	}{
+		{name: "empty page", page: pageInfo{MaxResults: 50, Total: 100}, count: 0, expected: true},
+		{name: "isLast set", page: pageInfo{MaxResults: 50, IsLast: true}, count: 10, expected: true},
+		{name: "more results by total", page: pageInfo{MaxResults: 50, Total: 100}, count: 50, expected: false},
+		{name: "last page by total", page: pageInfo{StartAt: 50, MaxResults: 50, Total: 100}, count: 50, expected: true},
+		{name: "more results by isLast", page: pageInfo{MaxResults: 50}, count: 50, expected: false},
+		{name: "no pagination metadata", page: pageInfo{}, count: 5, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.page.isLastPage(tt.count))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced clock for rate limiter tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestRateLimiter creates a limiter on a fake clock that records its waits
+func newTestRateLimiter(rps float64, burst int, clock *fakeClock, waits *[]time.Duration) *RateLimiter {
+	limiter := NewRateLimiter(rps, burst)
+	limiter.now = clock.Now
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return ctx.Err()
+	}
+	return limiter
+}
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, NewRateLimiter(0, 5))
+	assert.Nil(t, NewRateLimiter(-1, 5))
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	var waits []time.Duration
+	limiter := newTestRateLimiter(2, 3, clock, &waits)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.Empty(t, waits, "requests within the burst should not wait")
+
+	// The bucket is empty, so the next two requests queue behind each other
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second}, waits)
+}
+
+func TestRateLimiter_Refill(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	var waits []time.Duration
+	limiter := newTestRateLimiter(1, 1, clock, &waits)
+
+	assert.NoError(t, limiter.Wait(context.Background()))
+	clock.Advance(time.Second)
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Empty(t, waits)
+
+	// Idle time never fills the bucket beyond its burst
+	clock.Advance(time.Minute)
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{time.Second}, waits)
+}
+
+func TestRateLimiter_CancelledWaitReturnsToken(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	var waits []time.Duration
+	limiter := newTestRateLimiter(1, 1, clock, &waits)
+
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.Canceled)
+
+	// The cancelled request did not use its token, so the next wait is unchanged
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, waits)
+}
+
+func TestRateLimiter_Concurrent(t *testing.T) {
+	limiter := NewRateLimiter(1000, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	assert.LessOrEqual(t, limiter.tokens, limiter.burst)
+}
+
+func TestClientUsesRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"TEST-1"}`))
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+	var waits []time.Duration
+	limiter := newTestRateLimiter(10, 1, clock, &waits)
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), WithRateLimiter(limiter))
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetIssue(context.Background(), "TEST-1")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, waits)
+}
@@ -0,0 +1,196 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// A value of 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the initial backoff delay, doubled after every attempt
+	BaseDelay time.Duration
+	// MaxWait caps a single wait. A Retry-After longer than MaxWait is not
+	// honoured and the response is returned to the caller instead.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxWait:     30 * time.Second,
+	}
+}
+
+// WithRetryPolicy sets the retry policy used for requests
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// retrier resends failed requests according to a RetryPolicy.
+// It wraps http.Client.Do rather than the transport so that the client
+// timeout applies to each attempt and not to the time spent waiting.
+//
+// Idempotent requests are retried on network errors, 429 and 502/503/504
+// responses using jittered exponential backoff or the server's Retry-After.
+// Non-idempotent requests (POST) are only retried when Jira explicitly asks
+// for it with a 429 or 503 carrying a Retry-After header, since the request
+// was then rejected before being processed.
+type retrier struct {
+	policy RetryPolicy
+	logger *slog.Logger
+
+	// sleep and jitter are replaced in tests
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(d time.Duration) time.Duration
+}
+
+// newRetrier creates a retrier for the given retry policy
+func newRetrier(policy RetryPolicy, logger *slog.Logger) *retrier {
+	return &retrier{
+		policy: policy,
+		logger: logger,
+		sleep:  sleepContext,
+		jitter: fullJitter,
+	}
+}
+
+// do sends req with send, retrying until it succeeds, fails permanently
+// or the policy's attempts are exhausted.
+func (r *retrier) do(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := send(req)
+		if attempt >= r.policy.MaxAttempts {
+			return resp, err
+		}
+
+		wait, retry := r.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		// The body of a retried request has to be rewound
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		logRetry(req.Context(), r.logger, req, resp, err, attempt, wait)
+		if resp != nil {
+			drainAndClose(resp.Body)
+		}
+
+		if sleepErr := r.sleep(req.Context(), wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
+
+// retryDelay decides whether the outcome of an attempt should be retried
+// and how long to wait before doing so.
+func (r *retrier) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		// The request may have reached Jira, so only idempotent ones are safe to resend
+		// A request missing from a replayed cassette will never be found
+		if !isIdempotent(req.Method) || req.Context().Err() != nil || errors.Is(err, ErrNotRecorded) {
+			return 0, false
+		}
+		return r.backoff(attempt), true
+	}
+
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if hasRetryAfter && retryAfter > r.policy.MaxWait {
+		return 0, false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		if hasRetryAfter {
+			return retryAfter, true
+		}
+		if isIdempotent(req.Method) {
+			return r.backoff(attempt), true
+		}
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		if isIdempotent(req.Method) {
+			return r.backoff(attempt), true
+		}
+	}
+	return 0, false
+}
+
+// backoff returns the jittered exponential delay for the given attempt
+func (r *retrier) backoff(attempt int) time.Duration {
+	delay := r.policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > r.policy.MaxWait {
+		delay = r.policy.MaxWait
+	}
+	return r.jitter(delay)
+}
+
+// isIdempotent reports whether requests with the given method can safely be resent
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// fullJitter returns a random duration between zero and d
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d + 1)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,256 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRetrier creates a retrier that records its waits instead of sleeping
+func newTestRetrier(policy RetryPolicy, waits *[]time.Duration) *retrier {
+	r := newRetrier(policy, discardLogger)
+	r.jitter = func(d time.Duration) time.Duration { return d }
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return r
+}
+
+// scriptedSend returns a send function that replies with the given responses in order
+func scriptedSend(attempts *int, bodies *[]string, responses ...func() (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil && bodies != nil {
+			body, _ := io.ReadAll(req.Body)
+			*bodies = append(*bodies, string(body))
+		}
+		response := responses[*attempts]
+		*attempts++
+		return response()
+	}
+}
+
+func respond(statusCode int, retryAfter string) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp, nil
+	}
+}
+
+func fail() (*http.Response, error) {
+	return nil, errors.New("connection reset by peer")
+}
+
+func TestRetrier(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxWait: 10 * time.Second}
+
+	tests := []struct {
+		name             string
+		method           string
+		responses        []func() (*http.Response, error)
+		expectedStatus   int
+		expectedErr      bool
+		expectedAttempts int
+		expectedWaits    []time.Duration
+	}{
+		{
+			name:             "success is not retried",
+			method:           "GET",
+			responses:        []func() (*http.Response, error){respond(200, "")},
+			expectedStatus:   200,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "GET retried with exponential backoff",
+			method:           "GET",
+			responses:        []func() (*http.Response, error){respond(503, ""), respond(502, ""), respond(200, "")},
+			expectedStatus:   200,
+			expectedAttempts: 3,
+			expectedWaits:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:             "GET honours Retry-After",
+			method:           "GET",
+			responses:        []func() (*http.Response, error){respond(429, "2"), respond(200, "")},
+			expectedStatus:   200,
+			expectedAttempts: 2,
+			expectedWaits:    []time.Duration{2 * time.Second},
+		},
+		{
+			name:             "GET retried on network error",
+			method:           "GET",
+			responses:        []func() (*http.Response, error){fail, respond(200, "")},
+			expectedStatus:   200,
+			expectedAttempts: 2,
+			expectedWaits:    []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:             "attempts exhausted",
+			method:           "GET",
+			responses:        []func() (*http.Response, error){respond(503, ""), respond(503, ""), respond(503, "")},
+			expectedStatus:   503,
+			expectedAttempts: 3,
+			expectedWaits:    []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:             "Retry-After above max wait is not honoured",
+			method:           "GET",
+			responses:        []func() (*http.Response, error){respond(429, "60")},
+			expectedStatus:   429,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "client errors are not retried",
+			method:           "GET",
+			responses:        []func() (*http.Response, error){respond(404, "")},
+			expectedStatus:   404,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "POST not retried without Retry-After",
+			method:           "POST",
+			responses:        []func() (*http.Response, error){respond(503, "")},
+			expectedStatus:   503,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "POST not retried on network error",
+			method:           "POST",
+			responses:        []func() (*http.Response, error){fail},
+			expectedErr:      true,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "POST not retried on bad gateway",
+			method:           "POST",
+			responses:        []func() (*http.Response, error){respond(502, "1")},
+			expectedStatus:   502,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "POST retried when rate limited with Retry-After",
+			method:           "POST",
+			responses:        []func() (*http.Response, error){respond(429, "1"), respond(204, "")},
+			expectedStatus:   204,
+			expectedAttempts: 2,
+			expectedWaits:    []time.Duration{time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var waits []time.Duration
+			var bodies []string
+			attempts := 0
+			r := newTestRetrier(policy, &waits)
+
+			req, _ := http.NewRequest(tt.method, "https://jira.example.com/rest/api/2/issue", strings.NewReader(`{"id":"1"}`))
+			resp, err := r.do(req, scriptedSend(&attempts, &bodies, tt.responses...))
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			}
+			assert.Equal(t, tt.expectedAttempts, attempts)
+			assert.Equal(t, tt.expectedWaits, waits)
+			for _, body := range bodies {
+				assert.Equal(t, `{"id":"1"}`, body)
+			}
+		})
+	}
+}
+
+func TestRetrierDisabled(t *testing.T) {
+	var waits []time.Duration
+	attempts := 0
+	r := newTestRetrier(RetryPolicy{MaxAttempts: 1}, &waits)
+
+	req, _ := http.NewRequest("GET", "https://jira.example.com", nil)
+	resp, err := r.do(req, scriptedSend(&attempts, nil, respond(503, "")))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+	assert.Equal(t, 1, attempts)
+	assert.Empty(t, waits)
+}
+
+func TestBackoffCappedAtMaxWait(t *testing.T) {
+	var waits []time.Duration
+	r := newTestRetrier(RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxWait: 5 * time.Second}, &waits)
+
+	assert.Equal(t, time.Second, r.backoff(1))
+	assert.Equal(t, 4*time.Second, r.backoff(3))
+	assert.Equal(t, 5*time.Second, r.backoff(4))
+	assert.Equal(t, 5*time.Second, r.backoff(64))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "empty", value: "", ok: false},
+		{name: "seconds", value: "30", expected: 30 * time.Second, ok: true},
+		{name: "http date", value: "Fri, 01 Mar 2024 12:00:10 GMT", expected: 10 * time.Second, ok: true},
+		{name: "date in the past", value: "Fri, 01 Mar 2024 11:00:00 GMT", expected: 0, ok: true},
+		{name: "invalid", value: "soon", ok: false},
+		{name: "negative", value: "-5", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, wait)
+		})
+	}
+}
+
+func TestSleepContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := sleepContext(ctx, time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClientRetriesGetRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"key":"TEST-1"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()))
+	issue, err := client.GetIssue(context.Background(), "TEST-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "TEST-1", issue.Key)
+	assert.Equal(t, 3, attempts)
+}
@@ -0,0 +1,162 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Deployment types reported by the serverInfo endpoint
+const (
+	DeploymentCloud      = "Cloud"
+	DeploymentServer     = "Server"
+	DeploymentDataCenter = "DataCenter"
+)
+
+// ServerInfo describes the Jira instance a client talks to
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	VersionNumbers []int  `json:"versionNumbers"`
+	DeploymentType string `json:"deploymentType"`
+	BuildNumber    int    `json:"buildNumber"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// IsCloud reports whether the instance is Jira Cloud
+func (s ServerInfo) IsCloud() bool {
+	return strings.EqualFold(s.DeploymentType, DeploymentCloud)
+}
+
+// AtLeast reports whether the instance runs at least version major.minor.
+// Jira Cloud is always considered up to date.
+func (s ServerInfo) AtLeast(major, minor int) bool {
+	if s.IsCloud() {
+		return true
+	}
+	if len(s.VersionNumbers) == 0 {
+		return false
+	}
+	if s.VersionNumbers[0] != major {
+		return s.VersionNumbers[0] > major
+	}
+	return len(s.VersionNumbers) > 1 && s.VersionNumbers[1] >= minor
+}
+
+// WithServerInfo tells the client what kind of instance it talks to,
+// skipping the serverInfo probe
+func WithServerInfo(info ServerInfo) ClientOption {
+	return func(c *Client) {
+		c.serverInfo = &info
+	}
+}
+
+// ServerInfo returns the deployment type and version of the Jira instance.
+// The serverInfo endpoint is only asked once per client; its response is
+// also kept in the response cache, so most runs do not send the probe at all.
+func (c *Client) ServerInfo(ctx context.Context) (ServerInfo, error) {
+	c.probeMu.Lock()
+	defer c.probeMu.Unlock()
+	if c.serverInfo != nil {
+		return *c.serverInfo, nil
+	}
+	if c.probeErr != nil {
+		return ServerInfo{}, c.probeErr
+	}
+
+	var info ServerInfo
+	if err := c.makeGetRequest(ctx, c.apiURL("serverInfo"), &info); err != nil {
+		err = fmt.Errorf("error fetching server info: %w", err)
+		// A cancelled probe may succeed later, anything else would fail again
+		if ctx.Err() == nil {
+			c.probeErr = err
+		}
+		return ServerInfo{}, err
+	}
+	c.serverInfo = &info
+	return info, nil
+}
+
+// isCloud reports whether the client talks to Jira Cloud. When the instance
+// cannot be probed, it is treated as Server or Data Center.
+func (c *Client) isCloud(ctx context.Context) bool {
+	info, err := c.ServerInfo(ctx)
+	if err != nil {
+		c.logger.DebugContext(ctx, "Could not detect Jira deployment type, assuming Server", "error", err)
+		return false
+	}
+	return info.IsCloud()
+}
+
+// User is a Jira user. Jira Cloud identifies users by AccountID only, while
+// Server and Data Center use Name (the username) and Key.
+type User struct {
+	AccountID    string `json:"accountId,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Key          string `json:"key,omitempty"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Active       bool   `json:"active"`
+}
+
+// Myself returns the user the client is authenticated as
+func (c *Client) Myself(ctx context.Context) (User, error) {
+	var user User
+	if err := c.makeGetRequest(ctx, c.apiURL("myself"), &user); err != nil {
+		return User{}, fmt.Errorf("error fetching current user: %w", err)
+	}
+	return user, nil
+}
+
+// FindUsers returns the users whose name, display name or email address
+// matches query
+func (c *Client) FindUsers(ctx context.Context, query string) ([]User, error) {
+	param := "username"
+	if c.isCloud(ctx) {
+		param = "query"
+	}
+	searchURL := c.apiURL("user/search?%s=%s", param, url.QueryEscape(query))
+
+	var users []User
+	if err := c.makeGetRequest(ctx, searchURL, &users); err != nil {
+		return nil, fmt.Errorf("error searching users matching %q: %w", query, err)
+	}
+	return users, nil
+}
+
+// FindUser returns the one user matching query. A query matching several
+// users must equal the username, account ID, email address or display name
+// of one of them.
+func (c *Client) FindUser(ctx context.Context, query string) (User, error) {
+	users, err := c.FindUsers(ctx, query)
+	if err != nil {
+		return User{}, err
+	}
+	if len(users) == 1 {
+		return users[0], nil
+	}
+
+	var names []string
+	for _, user := range users {
+		for _, id := range []string{user.Name, user.AccountID, user.EmailAddress, user.DisplayName} {
+			if id != "" && strings.EqualFold(id, query) {
+				return user, nil
+			}
+		}
+		names = append(names, user.DisplayName)
+	}
+	if len(users) == 0 {
+		return User{}, fmt.Errorf("no user found matching %q", query)
+	}
+	return User{}, fmt.Errorf("%d users match %q: %s", len(users), query, strings.Join(names, ", "))
+}
+
+// UserReference returns how user is referenced in request payloads, such
+// as the assignee of an issue: by account ID on Cloud and by name elsewhere
+func (c *Client) UserReference(ctx context.Context, user User) map[string]string {
+	if c.isCloud(ctx) {
+		return map[string]string{"accountId": user.AccountID}
+	}
+	return map[string]string{"name": user.Name}
+}
@@ -0,0 +1,150 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProbedServer serves serverInfo for deploymentType, counting probes, and
+// answers every other request with handler
+func newProbedServer(t *testing.T, deploymentType string, probes *int, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/serverInfo" {
+			*probes++
+			_ = json.NewEncoder(w).Encode(ServerInfo{DeploymentType: deploymentType, Version: "1001.0.0"})
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestServerInfoProbedOnce(t *testing.T) {
+	probes := 0
+	server := newProbedServer(t, DeploymentCloud, &probes, nil)
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testFieldIDs)
+
+	for i := 0; i < 3; i++ {
+		info, err := client.ServerInfo(context.Background())
+		require.NoError(t, err)
+		assert.True(t, info.IsCloud())
+		assert.Equal(t, "1001.0.0", info.Version)
+	}
+	assert.Equal(t, 1, probes)
+}
+
+func TestServerInfoProbeFailure(t *testing.T) {
+	probes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/serverInfo" {
+			probes++
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "/rest/api/2/search", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(JiraResponse{Issues: []Issue{{Key: "TEST-1"}}})
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testFieldIDs)
+
+	// Searches fall back to the Server endpoint and the probe is not repeated
+	for i := 0; i < 2; i++ {
+		issues, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", 0)
+		require.NoError(t, err)
+		assert.Len(t, issues, 1)
+	}
+	assert.Equal(t, 1, probes)
+
+	_, err := client.ServerInfo(context.Background())
+	assert.ErrorContains(t, err, "error fetching server info")
+}
+
+func TestSearchOnCloud(t *testing.T) {
+	probes := 0
+	var tokens []string
+	server := newProbedServer(t, DeploymentCloud, &probes, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/search/jql", r.URL.Path)
+		assert.Equal(t, "*navigable", r.URL.Query().Get("fields"))
+		token := r.URL.Query().Get("nextPageToken")
+		tokens = append(tokens, token)
+
+		response := JiraResponse{Issues: []Issue{{Key: "TEST-1"}}, NextPageToken: "page-2"}
+		if token == "page-2" {
+			response = JiraResponse{Issues: []Issue{{Key: "TEST-2"}}, IsLast: true}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testFieldIDs)
+
+	issues, err := client.FetchIssuesFromJQL(context.Background(), "project=TEST", 0)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	assert.Equal(t, "TEST-2", issues[1].Key)
+	assert.Equal(t, []string{"", "page-2"}, tokens)
+}
+
+func TestFindUsers(t *testing.T) {
+	tests := []struct {
+		deploymentType string
+		param          string
+		reference      map[string]string
+	}{
+		{DeploymentCloud, "query", map[string]string{"accountId": "5b10ac8d82e05b22cc7d4ef5"}},
+		{DeploymentDataCenter, "username", map[string]string{"name": "jdoe"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.deploymentType, func(t *testing.T) {
+			probes := 0
+			server := newProbedServer(t, tt.deploymentType, &probes, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/rest/api/2/user/search", r.URL.Path)
+				assert.Equal(t, "jane doe", r.URL.Query().Get(tt.param))
+				_ = json.NewEncoder(w).Encode([]User{{AccountID: "5b10ac8d82e05b22cc7d4ef5", Name: "jdoe", DisplayName: "Jane Doe"}})
+			})
+			client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testFieldIDs)
+
+			users, err := client.FindUsers(context.Background(), "jane doe")
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+			assert.Equal(t, "Jane Doe", users[0].DisplayName)
+			assert.Equal(t, tt.reference, client.UserReference(context.Background(), users[0]))
+		})
+	}
+}
+
+func TestAssigneeUnmarshal(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      string
+		accountID string
+	}{
+		{"server", `{"name":"jdoe","key":"JIRAUSER1","displayName":"Jane Doe"}`, "jdoe", ""},
+		{"cloud", `{"accountId":"5b10ac8d","displayName":"Jane Doe"}`, "Jane Doe", "5b10ac8d"},
+		{"cloud without display name", `{"accountId":"5b10ac8d"}`, "5b10ac8d", "5b10ac8d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var assignee Assignee
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &assignee))
+			assert.Equal(t, tt.want, assignee.Name)
+			assert.Equal(t, tt.accountID, assignee.AccountID())
+		})
+	}
+}
+
+func TestServerInfoAtLeast(t *testing.T) {
+	server := ServerInfo{DeploymentType: DeploymentServer, VersionNumbers: []int{8, 20, 1}}
+	assert.True(t, server.AtLeast(8, 4))
+	assert.True(t, server.AtLeast(7, 30))
+	assert.False(t, server.AtLeast(9, 0))
+	assert.False(t, ServerInfo{}.AtLeast(8, 0))
+	assert.True(t, ServerInfo{DeploymentType: DeploymentCloud}.AtLeast(99, 0))
+}
@@ -1,7 +1,9 @@
 package jira
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"log"
 	"net/url"
 	"strconv"
@@ -10,7 +12,6 @@ import (
 
 const (
 	JIRA_URL_BOARD = "rest/agile/1.0/board"
-	JIRA_URL_JQL   = "rest/api/2/search?jql"
 )
 
 // FetchSprintByID retrieves a Jira sprint with the specified ID.
@@ -18,60 +19,73 @@ const (
 //
 // Parameters:
 //   - id: The ID of the sprint to fetch
-//   - makeGetRequest: Function to make the Jira API request
 //
 // Returns:
 //   - Sprint: Sprint object if successful
 //   - error: Error if the request fails
-func FetchSprintByID(id int, makeGetRequest JiraRequestFunc) (Sprint, error) {
+func (c *Client) FetchSprintByID(ctx context.Context, id int) (Sprint, error) {
 	var sprintResp Sprint
 	// JQL to find boards for the project and component
-	sprintSearchURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d", jiraBaseURL, id)
+	sprintSearchURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d", c.baseURL, id)
 
-	if err := makeGetRequest(sprintSearchURL, &sprintResp); err != nil {
+	if err := c.makeGetRequest(ctx, sprintSearchURL, &sprintResp); err != nil {
 		return Sprint{}, err
 	}
 	return sprintResp, nil
 }
 
 // FetchSprintIssues retrieves issues from a given sprint with epic information included.
-// It uses the provided makeGetRequest function to make the API call to JIRA.
 //
 // Parameters:
 //   - sprintID: The ID of the sprint to fetch issues from
-//   - makeGetRequest: Function to make the HTTP GET request to JIRA
+//   - fetchFeatures: Whether to also fetch Feature data for the issues' epics
 //
 // Returns:
 //   - []Issue: A slice of Issue objects representing the issues in the sprint with epic information
 //   - error: An error if the request fails or the response cannot be parsed
-func FetchSprintIssues(sprintID int, makeGetRequest JiraRequestFunc, fetchFeatures bool) ([]Issue, error) {
-	var jiraResponse JiraResponse
+func (c *Client) FetchSprintIssues(ctx context.Context, sprintID int, fetchFeatures bool) ([]Issue, error) {
 	fields := []string{
 		"summary",
 		"assignee",
 		"status",
 		"priority",
-		"customfield_12310243", // Story Points
 		"duedate",
 		"epic",
 		"issuetype",
 	}
+	registry := c.fieldRegistry(ctx)
+	fields = append(fields, registry.IDs(FieldStoryPoints, FieldEpicLink, FieldSprint)...)
 	fieldsStr := strings.Join(fields, ",")
 
 	// Use the fields parameter to expand epic information
 	// Using rest/agile/1.0 API which includes epic data in the response
-	sprintSearchURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue?fields=%s", jiraBaseURL, sprintID, fieldsStr)
+	sprintSearchURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue?fields=%s", c.baseURL, sprintID, fieldsStr)
 
-	if err := makeGetRequest(sprintSearchURL, &jiraResponse); err != nil {
+	issues, err := collect(paginate(0, func(startAt int) ([]Issue, pageInfo, error) {
+		pageURL := sprintSearchURL
+		if startAt > 0 {
+			pageURL = fmt.Sprintf("%s&startAt=%d", sprintSearchURL, startAt)
+		}
+
+		var jiraResponse JiraResponse
+		if err := c.makeGetRequest(ctx, pageURL, &jiraResponse); err != nil {
+			return nil, pageInfo{}, err
+		}
+		for i := range jiraResponse.Issues {
+			jiraResponse.Issues[i].Fields.resolve(registry)
+		}
+		return jiraResponse.Issues, jiraResponse.pageInfo(), nil
+	}), 0)
+	if err != nil {
 		return nil, err
 	}
 
 	if fetchFeatures {
-		if err := enrichIssuesWithFeatures(jiraResponse.Issues, makeGetRequest); err != nil {
+		if err := c.enrichIssuesWithFeatures(ctx, issues); err != nil {
 			return nil, err
 		}
 	}
-	return jiraResponse.Issues, nil
+	return issues, nil
 }
 
 // uniqueEpicsFromIssues returns a map of all unique epics from list of Issues
@@ -85,12 +99,16 @@ func uniqueEpicsFromIssues(issues []Issue) map[string]*Epic {
 	return epics
 }
 
-func fetchFeatures(epics map[string]*Epic, epicFetcher EpicFetcher) (map[string]*Feature, error) {
+func fetchFeatures(ctx context.Context, epics map[string]*Epic, epicFetcher EpicFetcher) (map[string]*Feature, error) {
 	features := make(map[string]*Feature)
 	for _, epic := range epics {
 		if epic.Key != "" {
-			epicIssue, err := epicFetcher.FetchEpic(epic.Key)
+			epicIssue, err := epicFetcher.FetchEpic(ctx, epic.Key)
 			if err != nil {
+				// A cancelled context fails every remaining epic; stop instead of logging each one
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
 				log.Printf("Failed to fetch epic details for epic %s: %v", epic.Key, err)
 				continue
 			}
@@ -117,10 +135,9 @@ func updateIssuesWithFeatures(issues []Issue, epicToFeature map[string]*Feature)
 }
 
 // enrichIssuesWithFeatures fetches and assigns Feature data for issues with Epics.
-func enrichIssuesWithFeatures(issues []Issue, makeGetRequest JiraRequestFunc) error {
+func (c *Client) enrichIssuesWithFeatures(ctx context.Context, issues []Issue) error {
 	updatedEpics := uniqueEpicsFromIssues(issues)
-	epicFetcher := NewEpicFetcher(makeGetRequest)
-	epicToFeature, err := fetchFeatures(updatedEpics, epicFetcher)
+	epicToFeature, err := fetchFeatures(ctx, updatedEpics, c)
 	if err != nil {
 		return err
 	}
@@ -128,45 +145,55 @@ func enrichIssuesWithFeatures(issues []Issue, makeGetRequest JiraRequestFunc) er
 	return nil
 }
 
-// FetchSprints retrieves all active sprints for a given board ID.
-// It uses the provided makeGetRequest function to make the API call to JIRA.
+// FetchSprints retrieves the sprints of a given board ID, following
+// pagination until every sprint has been fetched or the limit is reached.
 //
 // Parameters:
 //   - boardId: The ID of the JIRA board to fetch sprints from
-//   - makeGetRequest: Function to make the HTTP GET request to JIRA
-//   - options: Optional parameters for the request (maxResults, startAt)
+//   - options: Optional parameters for the request (state, maxResults, startAt, limit)
 //
 // Returns:
-//   - []Sprint: A slice of Sprint objects representing the active sprints
+//   - []Sprint: A slice of Sprint objects representing the matching sprints
 //   - error: An error if the request fails or the response cannot be parsed
-func FetchSprints(boardId int, makeGetRequest JiraRequestFunc, options ...SprintRequestOption) ([]Sprint, error) {
-	// Default options
+func (c *Client) FetchSprints(ctx context.Context, boardId int, options ...SprintRequestOption) ([]Sprint, error) {
 	opts := defaultSprintRequestOptions()
 	for _, option := range options {
 		option(opts)
 	}
+	return collect(c.Sprints(ctx, boardId, options...), opts.limit)
+}
 
-	// Build URL with query parameters
-	baseURL := fmt.Sprintf("%s/%s/%d/sprint", jiraBaseURL, JIRA_URL_BOARD, boardId)
-
-	// Build query parameters
-	params := url.Values{}
-	params.Add("state", opts.state.String())
-	if opts.maxResults > 0 {
-		params.Add("maxResults", strconv.Itoa(opts.maxResults))
-	}
-	if opts.startAt > 0 {
-		params.Add("startAt", strconv.Itoa(opts.startAt))
+// Sprints returns an iterator over the sprints of a given board ID.
+// Pages are requested from Jira as the iterator advances.
+func (c *Client) Sprints(ctx context.Context, boardId int, options ...SprintRequestOption) iter.Seq2[Sprint, error] {
+	// Default options
+	opts := defaultSprintRequestOptions()
+	for _, option := range options {
+		option(opts)
 	}
 
-	sprintURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
+	// Build URL with query parameters
+	baseURL := fmt.Sprintf("%s/%s/%d/sprint", c.baseURL, JIRA_URL_BOARD, boardId)
+
+	return paginate(opts.startAt, func(startAt int) ([]Sprint, pageInfo, error) {
+		// Build query parameters
+		params := url.Values{}
+		params.Add("state", opts.state.String())
+		if opts.maxResults > 0 {
+			params.Add("maxResults", strconv.Itoa(opts.maxResults))
+		}
+		if startAt > 0 {
+			params.Add("startAt", strconv.Itoa(startAt))
+		}
 
-	var allSprints SprintResponse
-	if err := makeGetRequest(sprintURL, &allSprints); err != nil {
-		return nil, fmt.Errorf("failed to fetch active sprints for board %d: %w", boardId, err)
-	}
+		sprintURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	return allSprints.Values, nil
+		var allSprints SprintResponse
+		if err := c.makeGetRequest(ctx, sprintURL, &allSprints); err != nil {
+			return nil, pageInfo{}, fmt.Errorf("failed to fetch %s sprints for board %d: %w", opts.state, boardId, err)
+		}
+		return allSprints.Values, allSprints.pageInfo(), nil
+	})
 }
 
 // SprintRequestOptions holds optional parameters for sprint requests
@@ -174,6 +201,7 @@ type sprintRequestOptions struct {
 	state      SprintState
 	maxResults int
 	startAt    int
+	limit      int
 }
 
 // SprintRequestOption is a function that modifies sprintRequestOptions
@@ -185,10 +213,11 @@ func defaultSprintRequestOptions() *sprintRequestOptions {
 		state:      SprintStateActive,
 		maxResults: 0, // Use API default
 		startAt:    0,
+		limit:      0, // Fetch all pages
 	}
 }
 
-// WithMaxResults sets the maximum number of results to return
+// WithMaxResults sets the maximum number of results to return per page
 func WithMaxResults(max int) SprintRequestOption {
 	return func(o *sprintRequestOptions) {
 		o.maxResults = max // Use API default if max is negative or zero
@@ -202,6 +231,13 @@ func WithStartAt(start int) SprintRequestOption {
 	}
 }
 
+// WithLimit sets the maximum number of sprints to return across all pages
+func WithLimit(limit int) SprintRequestOption {
+	return func(o *sprintRequestOptions) {
+		o.limit = limit // Fetch all pages if limit is negative or zero
+	}
+}
+
 // WithSprintState sets the state of the sprints to fetch
 func WithSprintState(state SprintState) SprintRequestOption {
 	return func(o *sprintRequestOptions) {
@@ -3,6 +3,7 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,7 +17,7 @@ import (
 )
 
 // Mock function for JiraRequestFunc for FetchOpenSprints call
-func mockOpenSprintsRequest(url string, response any) error {
+func mockOpenSprintsRequest(ctx context.Context, url string, response any) error {
 	if url == "error" {
 		return errors.New("failed to fetch sprints")
 	}
@@ -40,7 +41,7 @@ func mockOpenSprintsRequest(url string, response any) error {
 func TestFetchOpenSprints_Success(t *testing.T) {
 	boardID := 123
 
-	sprints, err := FetchSprints(boardID, mockOpenSprintsRequest)
+	sprints, err := newTestClient(mockOpenSprintsRequest).FetchSprints(context.Background(), boardID)
 	assert.NoError(t, err)
 	assert.Len(t, sprints, 2)
 	assert.Equal(t, "Sprint 1", sprints[0].Name)
@@ -51,11 +52,11 @@ func TestFetchOpenSprints_APIError(t *testing.T) {
 	boardID := 123
 
 	// Mock function to simulate an API error
-	mockErrorFunc := func(url string, response any) error {
+	mockErrorFunc := func(ctx context.Context, url string, response any) error {
 		return errors.New("API failure")
 	}
 
-	sprints, err := FetchSprints(boardID, mockErrorFunc)
+	sprints, err := newTestClient(mockErrorFunc).FetchSprints(context.Background(), boardID)
 	assert.Error(t, err)
 	assert.Nil(t, sprints)
 }
@@ -64,7 +65,7 @@ func TestFetchOpenSprints_EmptyResponse(t *testing.T) {
 	boardID := 123
 
 	// Mock function returning an empty sprint list
-	mockEmptyFunc := func(url string, response any) error {
+	mockEmptyFunc := func(ctx context.Context, url string, response any) error {
 		if r, ok := response.(*SprintResponse); ok {
 			*r = SprintResponse{Values: []Sprint{}}
 			return nil
@@ -72,7 +73,7 @@ func TestFetchOpenSprints_EmptyResponse(t *testing.T) {
 		return errors.New("invalid response type")
 	}
 
-	sprints, err := FetchSprints(boardID, mockEmptyFunc)
+	sprints, err := newTestClient(mockEmptyFunc).FetchSprints(context.Background(), boardID)
 	assert.NoError(t, err)
 	assert.Empty(t, sprints)
 }
@@ -303,7 +304,7 @@ func TestFetchSprintByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock request function
-			mockMakeRequest := func(url string, target any) error {
+			mockMakeRequest := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
@@ -314,7 +315,7 @@ func TestFetchSprintByID(t *testing.T) {
 			}
 
 			// Call the function being tested
-			sprint, err := FetchSprintByID(tt.sprintID, mockMakeRequest)
+			sprint, err := newTestClient(mockMakeRequest).FetchSprintByID(context.Background(), tt.sprintID)
 
 			// Check error
 			if (err != nil) != tt.expectedError {
@@ -370,13 +371,13 @@ func TestFetchSprintIssues(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock request function
-			mockMakeRequest := func(url string, target any) error {
+			mockMakeRequest := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
 
 				// Check URL format contains expected fields
-				expectedURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue?fields=", jiraBaseURL, tt.sprintID)
+				expectedURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue?fields=", testBaseURL, tt.sprintID)
 				if !strings.HasPrefix(url, expectedURL) {
 					t.Errorf("incorrect URL prefix: got %s, want %s", url, expectedURL)
 				}
@@ -387,7 +388,7 @@ func TestFetchSprintIssues(t *testing.T) {
 			}
 
 			// Call the function being tested
-			issues, err := FetchSprintIssues(tt.sprintID, mockMakeRequest, tt.fetchFeatures)
+			issues, err := newTestClient(mockMakeRequest).FetchSprintIssues(context.Background(), tt.sprintID, tt.fetchFeatures)
 
 			// Check error
 			if (err != nil) != tt.expectedError {
@@ -593,7 +594,7 @@ type MockEpicFetcher struct {
 	err   error
 }
 
-func (m *MockEpicFetcher) FetchEpic(epicKey string) (EpicResponse, error) {
+func (m *MockEpicFetcher) FetchEpic(ctx context.Context, epicKey string) (EpicResponse, error) {
 	if m.err != nil {
 		return EpicResponse{}, m.err
 	}
@@ -681,17 +682,17 @@ func TestFetchFeatures(t *testing.T) {
 			}
 
 			// Call the function being tested
-			features, err := fetchFeatures(tt.epics, mockFetcher)
+			features, err := fetchFeatures(context.Background(), tt.epics, mockFetcher)
 
 			// Check error
 			if (err != nil) != tt.expectedError {
-				t.Errorf("fetchFeatures() error = %v, expectedError = %v", err, tt.expectedError)
+				t.Errorf("fetchFeatures(context.Background(), ) error = %v, expectedError = %v", err, tt.expectedError)
 				return
 			}
 
 			// Check features
 			if len(features) != len(tt.expected) {
-				t.Errorf("fetchFeatures() returned %d features, expected %d", len(features), len(tt.expected))
+				t.Errorf("fetchFeatures(context.Background(), ) returned %d features, expected %d", len(features), len(tt.expected))
 				return
 			}
 
@@ -794,7 +795,7 @@ func TestEnrichIssuesWithFeatures(t *testing.T) {
 			}
 
 			// Create mock request function that will be used by the EpicFetcher
-			mockMakeRequest := func(url string, target any) error {
+			mockMakeRequest := func(ctx context.Context, url string, target any) error {
 				if tt.mockError != nil {
 					return tt.mockError
 				}
@@ -815,7 +816,7 @@ func TestEnrichIssuesWithFeatures(t *testing.T) {
 			}
 
 			// Call the function being tested
-			err := enrichIssuesWithFeatures(testIssues, mockMakeRequest)
+			err := newTestClient(mockMakeRequest).enrichIssuesWithFeatures(context.Background(), testIssues)
 
 			// Check error
 			if (err != nil) != tt.expectedError {
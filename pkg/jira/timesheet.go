@@ -0,0 +1,94 @@
+package jira
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// TimesheetEntry is the time a user logged on an issue in a day
+type TimesheetEntry struct {
+	Author    User
+	Day       time.Time
+	IssueKey  string
+	Summary   string
+	TimeSpent time.Duration
+}
+
+// orderBy matches the ORDER BY clause ending a JQL query
+var orderBy = regexp.MustCompile(`(?i)\s+order\s+by\s.*$`)
+
+// timesheetJQL restricts scope to the issues with work logged between the
+// days from and to
+func timesheetJQL(scope string, from, to time.Time) string {
+	dates := fmt.Sprintf(`worklogDate >= "%s" AND worklogDate <= "%s"`, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	scope = orderBy.ReplaceAllString(scope, "")
+	if scope == "" {
+		return dates
+	}
+	return fmt.Sprintf("(%s) AND %s", scope, dates)
+}
+
+// Timesheet sums the work logged on the issues matching the JQL scope
+// between the days from and to, both included, per user, day and issue.
+// Days are counted in the location of from. Entries are sorted by user,
+// day and issue.
+func (c *Client) Timesheet(ctx context.Context, scope string, from, to time.Time) ([]TimesheetEntry, error) {
+	location := from.Location()
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, location).AddDate(0, 0, 1)
+	if !end.After(start) {
+		return nil, fmt.Errorf("invalid timesheet range: %s is before %s", to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+
+	type entryKey struct {
+		author string
+		day    time.Time
+		issue  string
+	}
+	entries := map[entryKey]*TimesheetEntry{}
+	for issue, err := range c.IssuesFromJQL(ctx, timesheetJQL(scope, start, end.AddDate(0, 0, -1))) {
+		if err != nil {
+			return nil, fmt.Errorf("error searching issues with worklogs: %w", err)
+		}
+		// The worklogs in the search results are truncated, so they are
+		// fetched separately
+		for worklog, err := range c.Worklogs(ctx, issue.Key) {
+			if err != nil {
+				return nil, err
+			}
+			if worklog.Started == nil || worklog.Started.Before(start) || !worklog.Started.Before(end) {
+				continue
+			}
+
+			started := worklog.Started.In(location)
+			key := entryKey{
+				author: cmp.Or(worklog.Author.AccountID, worklog.Author.Name, worklog.Author.DisplayName),
+				day:    time.Date(started.Year(), started.Month(), started.Day(), 0, 0, 0, 0, location),
+				issue:  issue.Key,
+			}
+			entry, ok := entries[key]
+			if !ok {
+				entry = &TimesheetEntry{Author: worklog.Author, Day: key.day, IssueKey: issue.Key, Summary: issue.Fields.Summary}
+				entries[key] = entry
+			}
+			entry.TimeSpent += worklog.Duration()
+		}
+	}
+
+	timesheet := make([]TimesheetEntry, 0, len(entries))
+	for _, entry := range entries {
+		timesheet = append(timesheet, *entry)
+	}
+	slices.SortFunc(timesheet, func(a, b TimesheetEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.Author.DisplayName, b.Author.DisplayName),
+			a.Day.Compare(b.Day),
+			cmp.Compare(a.IssueKey, b.IssueKey),
+		)
+	})
+	return timesheet, nil
+}
@@ -0,0 +1,73 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimesheetJQL(t *testing.T) {
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, `(project = TEST OR labels = ops) AND worklogDate >= "2025-06-02" AND worklogDate <= "2025-06-06"`,
+		timesheetJQL("project = TEST OR labels = ops ORDER BY key", from, to))
+	assert.Equal(t, `worklogDate >= "2025-06-02" AND worklogDate <= "2025-06-06"`, timesheetJQL("", from, to))
+}
+
+func TestTimesheet(t *testing.T) {
+	worklogs := map[string]string{
+		"TEST-1": `{"total": 4, "worklogs": [
+			{"author": {"name": "jdoe", "displayName": "Jane Doe"}, "started": "2025-06-02T09:00:00.000+0000", "timeSpentSeconds": 3600},
+			{"author": {"name": "jdoe", "displayName": "Jane Doe"}, "started": "2025-06-02T14:00:00.000+0000", "timeSpentSeconds": 1800},
+			{"author": {"name": "bob", "displayName": "Bob"}, "started": "2025-06-03T09:00:00.000+0000", "timeSpentSeconds": 7200},
+			{"author": {"name": "jdoe", "displayName": "Jane Doe"}, "started": "2025-05-30T09:00:00.000+0000", "timeSpentSeconds": 3600}]}`,
+		"TEST-2": `{"total": 1, "worklogs": [
+			{"author": {"name": "jdoe", "displayName": "Jane Doe"}, "started": "2025-06-02T16:00:00.000+0000", "timeSpentSeconds": 900}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			assert.Equal(t, `(project = TEST) AND worklogDate >= "2025-06-02" AND worklogDate <= "2025-06-03"`, r.URL.Query().Get("jql"))
+			_, _ = w.Write([]byte(`{"total": 2, "issues": [
+				{"key": "TEST-1", "fields": {"summary": "Export reports"}},
+				{"key": "TEST-2", "fields": {"summary": "Fix login"}}]}`))
+		case "/rest/api/2/issue/TEST-1/worklog":
+			_, _ = w.Write([]byte(worklogs["TEST-1"]))
+		case "/rest/api/2/issue/TEST-2/worklog":
+			_, _ = w.Write([]byte(worklogs["TEST-2"]))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testServerInfo, testFieldIDs)
+
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+	timesheet, err := client.Timesheet(context.Background(), "project = TEST", from, to)
+	require.NoError(t, err)
+
+	type entry struct {
+		user, day, issue string
+		spent            time.Duration
+	}
+	var got []entry
+	for _, e := range timesheet {
+		got = append(got, entry{e.Author.DisplayName, e.Day.Format(time.DateOnly), e.IssueKey, e.TimeSpent})
+	}
+	assert.Equal(t, []entry{
+		{"Bob", "2025-06-03", "TEST-1", 2 * time.Hour},
+		{"Jane Doe", "2025-06-02", "TEST-1", 90 * time.Minute},
+		{"Jane Doe", "2025-06-02", "TEST-2", 15 * time.Minute},
+	}, got)
+	assert.Equal(t, "Export reports", timesheet[0].Summary)
+
+	_, err = client.Timesheet(context.Background(), "project = TEST", to, from)
+	assert.EqualError(t, err, "invalid timesheet range: 2025-06-02 is before 2025-06-03")
+}
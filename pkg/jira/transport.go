@@ -0,0 +1,196 @@
+package jira
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// TransportOptions tunes the HTTP client used to talk to Jira
+type TransportOptions struct {
+	// Timeout limits a single request attempt, including reading the response
+	Timeout time.Duration
+	// MaxIdleConnsPerHost is the number of keep-alive connections kept per host
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle keep-alive connection is kept open
+	IdleConnTimeout time.Duration
+
+	// CABundle is a PEM file of additional certificate authorities to trust
+	CABundle string
+	// ClientCert and ClientKey are PEM files of a certificate presented for mutual TLS
+	ClientCert string
+	ClientKey  string
+	// MinTLSVersion is the minimum TLS version accepted, e.g. tls.VersionTLS12
+	MinTLSVersion uint16
+	// InsecureSkipVerify disables server certificate verification.
+	// It must only be used against lab instances.
+	InsecureSkipVerify bool
+
+	// HTTPProxy and HTTPSProxy are the proxies of http and https requests.
+	// They are ignored when the environment sets HTTP_PROXY or HTTPS_PROXY.
+	HTTPProxy  string
+	HTTPSProxy string
+}
+
+// DefaultTransportOptions returns the options used when none are configured
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		Timeout:             30 * time.Second,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		MinTLSVersion:       tls.VersionTLS12,
+	}
+}
+
+// TLSConfig builds the TLS configuration described by the options
+func (o TransportOptions) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         o.MinTLSVersion,
+		InsecureSkipVerify: o.InsecureSkipVerify, // #nosec G402 -- explicit opt-in for lab instances
+	}
+
+	if o.CABundle != "" {
+		pem, err := os.ReadFile(o.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", o.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if o.ClientCert != "" || o.ClientKey != "" {
+		if o.ClientCert == "" || o.ClientKey == "" {
+			return nil, fmt.Errorf("both a client certificate and a client key are required for mutual TLS")
+		}
+		cert, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// proxyEnvironment are the variables http.ProxyFromEnvironment reads proxies from
+var proxyEnvironment = []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy"}
+
+// Proxy returns the proxy function of the transport. Proxies set in the
+// environment take precedence over the configured ones.
+func (o TransportOptions) Proxy() (func(*http.Request) (*url.URL, error), error) {
+	if o.HTTPProxy == "" && o.HTTPSProxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	for _, name := range proxyEnvironment {
+		if os.Getenv(name) != "" {
+			return http.ProxyFromEnvironment, nil
+		}
+	}
+
+	proxies := map[string]*url.URL{}
+	for scheme, proxy := range map[string]string{"http": o.HTTPProxy, "https": o.HTTPSProxy} {
+		if proxy == "" {
+			continue
+		}
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid %s proxy %q: expected a URL such as http://proxy.example.com:3128", scheme, proxy)
+		}
+		proxies[scheme] = proxyURL
+	}
+	// Like http.ProxyFromEnvironment, https requests do not fall back to
+	// the http proxy
+	return func(req *http.Request) (*url.URL, error) {
+		return proxies[req.URL.Scheme], nil
+	}, nil
+}
+
+// ParseTLSVersion converts a version such as "1.2" into its tls constant
+func ParseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q: must be 1.0, 1.1, 1.2 or 1.3", version)
+	}
+}
+
+var (
+	defaultHTTPClient     *http.Client
+	defaultHTTPClientOnce sync.Once
+)
+
+// DefaultHTTPClient returns the process-wide client used by clients that
+// were not given one with WithHTTPClient. Sharing it lets every Client
+// reuse the same connection pool.
+func DefaultHTTPClient() *http.Client {
+	defaultHTTPClientOnce.Do(func() {
+		// The default options load no files, so building the client cannot fail
+		defaultHTTPClient, _ = NewHTTPClient(DefaultTransportOptions())
+	})
+	return defaultHTTPClient
+}
+
+// NewHTTPClient creates an http.Client with a pooled transport tuned for
+// talking to Jira. It is meant to be created once and shared by all clients.
+//
+// Proxies are taken from HTTP_PROXY/HTTPS_PROXY/NO_PROXY, or else from the
+// options. HTTP/2 is
+// negotiated when the server supports it, and responses are transparently
+// gzip-decompressed because requests leave Accept-Encoding to the transport.
+func NewHTTPClient(opts TransportOptions) (*http.Client, error) {
+	tlsConfig, err := opts.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := opts.Proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+	}, nil
+}
+
+// drainAndClose reads what is left of a response body and closes it so the
+// underlying connection can go back to the pool
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxErrorBodySize))
+	body.Close()
+}
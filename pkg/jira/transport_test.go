@@ -0,0 +1,248 @@
+package jira
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultHTTPClientIsShared(t *testing.T) {
+	first := NewClient("https://first.example.com", testAuth)
+	second := NewClient("https://second.example.com", testAuth)
+
+	assert.Same(t, DefaultHTTPClient(), first.httpClient)
+	assert.Same(t, first.httpClient, second.httpClient)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	client, err := NewHTTPClient(TransportOptions{
+		Timeout:             5 * time.Second,
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, client.Timeout)
+
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.True(t, transport.ForceAttemptHTTP2)
+	assert.False(t, transport.DisableCompression)
+	assert.NotNil(t, transport.Proxy)
+}
+
+func TestTransportProxy(t *testing.T) {
+	for _, name := range proxyEnvironment {
+		t.Setenv(name, "")
+	}
+	client, err := NewHTTPClient(TransportOptions{HTTPSProxy: "http://proxy.example.com:3128"})
+	require.NoError(t, err)
+	transport := client.Transport.(*http.Transport)
+
+	req := httptest.NewRequest(http.MethodGet, "https://jira.example.com/rest/api/2/myself", nil)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	require.NotNil(t, proxy)
+	assert.Equal(t, "proxy.example.com:3128", proxy.Host)
+
+	req = httptest.NewRequest(http.MethodGet, "http://jira.example.com/rest/api/2/myself", nil)
+	proxy, err = transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxy, "https proxy used for http")
+
+	_, err = NewHTTPClient(TransportOptions{HTTPProxy: "not a url"})
+	assert.EqualError(t, err, `invalid http proxy "not a url": expected a URL such as http://proxy.example.com:3128`)
+}
+
+func TestTransportProxyPrefersEnvironment(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy.example.com:8080")
+	client, err := NewHTTPClient(TransportOptions{HTTPSProxy: "http://proxy.example.com:3128"})
+	require.NoError(t, err)
+
+	// http.ProxyFromEnvironment reads the environment once per process, so
+	// only check the configured proxy is not used
+	req := httptest.NewRequest(http.MethodGet, "https://jira.example.com/rest/api/2/myself", nil)
+	proxy, err := client.Transport.(*http.Transport).Proxy(req)
+	require.NoError(t, err)
+	if proxy != nil {
+		assert.NotEqual(t, "proxy.example.com:3128", proxy.Host)
+	}
+}
+
+func TestHTTPClientReusesConnections(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"TEST-1"}` + "\n"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	httpClient, err := NewHTTPClient(DefaultTransportOptions())
+	require.NoError(t, err)
+	first := NewClient(server.URL, testAuth, WithHTTPClient(httpClient))
+	second := NewClient(server.URL, testAuth, WithHTTPClient(httpClient))
+
+	for i := 0; i < 5; i++ {
+		_, err := first.GetIssue(context.Background(), "TEST-1")
+		require.NoError(t, err)
+		_, err = second.GetIssue(context.Background(), "TEST-1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), connections.Load())
+}
+
+func TestHTTPClientDecompressesGzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept-Encoding"), "gzip")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"key":"TEST-1"}`))
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	httpClient, err := NewHTTPClient(DefaultTransportOptions())
+	require.NoError(t, err)
+	client := NewClient(server.URL, testAuth, WithHTTPClient(httpClient))
+	issue, err := client.GetIssue(context.Background(), "TEST-1")
+	require.NoError(t, err)
+	assert.Equal(t, "TEST-1", issue.Key)
+}
+
+// writeServerCA writes the certificate of a TLS test server to a PEM file
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestHTTPClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"TEST-1"}`))
+	}))
+	defer server.Close()
+	caBundle := writeServerCA(t, server)
+
+	tests := []struct {
+		name    string
+		opts    func(*TransportOptions)
+		wantErr string
+	}{
+		{
+			name:    "untrusted certificate",
+			opts:    func(o *TransportOptions) {},
+			wantErr: "certificate",
+		},
+		{
+			name: "CA bundle",
+			opts: func(o *TransportOptions) { o.CABundle = caBundle },
+		},
+		{
+			name: "insecure skip verify",
+			opts: func(o *TransportOptions) { o.InsecureSkipVerify = true },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultTransportOptions()
+			tt.opts(&opts)
+			httpClient, err := NewHTTPClient(opts)
+			require.NoError(t, err)
+
+			client := NewClient(server.URL, testAuth, WithHTTPClient(httpClient), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+			_, err = client.GetIssue(context.Background(), "TEST-1")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHTTPClientMinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	opts := DefaultTransportOptions()
+	opts.CABundle = writeServerCA(t, server)
+	opts.MinTLSVersion = tls.VersionTLS13
+	httpClient, err := NewHTTPClient(opts)
+	require.NoError(t, err)
+
+	_, err = httpClient.Get(server.URL)
+	assert.ErrorContains(t, err, "protocol version")
+}
+
+func TestTransportOptionsTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name    string
+		opts    TransportOptions
+		wantErr string
+	}{
+		{"missing CA bundle", TransportOptions{CABundle: filepath.Join(dir, "missing.pem")}, "error reading CA bundle"},
+		{"CA bundle without certificates", TransportOptions{CABundle: empty}, "no certificates found"},
+		{"client cert without key", TransportOptions{ClientCert: empty}, "both a client certificate and a client key"},
+		{"invalid client cert", TransportOptions{ClientCert: empty, ClientKey: empty}, "error loading client certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTTPClient(tt.opts)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	version, err := ParseTLSVersion("1.3")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+
+	_, err = ParseTLSVersion("1.4")
+	assert.ErrorContains(t, err, "unsupported TLS version")
+}
+
+func TestCheckConnection(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/serverInfo", r.URL.Path)
+		assert.Equal(t, "Bearer "+testToken, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()))
+	report, err := client.CheckConnection(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, report.StatusCode)
+	require.NotNil(t, report.TLS)
+	assert.NotEmpty(t, report.TLS.Version)
+	assert.NotEmpty(t, report.TLS.Subject)
+}
@@ -12,9 +12,35 @@ type EpicResponse struct {
 	Feature FeatureResponse `json:"fields"`
 }
 
+// FeatureResponse is the feature an epic belongs to. Key is read from the
+// parent feature field of the instance, see FieldRegistry.
 type FeatureResponse struct {
-	Key     string `json:"customfield_12313140"`
+	Key     string `json:"-"`
 	Summary string `json:"summary"`
+
+	custom map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for FeatureResponse,
+// keeping the custom fields until the parent feature field is known
+func (f *FeatureResponse) UnmarshalJSON(data []byte) error {
+	type FeatureResponseAlias FeatureResponse
+	if err := json.Unmarshal(data, (*FeatureResponseAlias)(f)); err != nil {
+		return err
+	}
+	custom, err := customFields(data)
+	if err != nil {
+		return err
+	}
+	f.custom = custom
+	return nil
+}
+
+// resolve sets Key from the parent feature field of the instance
+func (f *FeatureResponse) resolve(r *FieldRegistry) {
+	if value := customValue(f.custom, r.ID(FieldParentFeature)); value != nil && f.Key == "" {
+		f.Key = decodeIssueKey(value)
+	}
 }
 
 // Epic represents a JIRA epic
@@ -25,31 +51,10 @@ type Epic struct {
 
 // Feature represents a JIRA feature
 type Feature struct {
-	Key     string `json:"key"` // Internal field name
+	Key     string `json:"key"`
 	Summary string `json:"summary"`
 }
 
-// UnmarshalJSON implements custom JSON unmarshaling for Feature to map customfield_12313140 to Key
-func (f *Feature) UnmarshalJSON(data []byte) error {
-	type FeatureAlias Feature
-	type FeatureTemp struct {
-		*FeatureAlias
-		CustomField string `json:"customfield_12313140"`
-	}
-
-	temp := &FeatureTemp{FeatureAlias: (*FeatureAlias)(f)}
-	if err := json.Unmarshal(data, temp); err != nil {
-		return err
-	}
-
-	// Map the custom field to Key if Key isn't already set
-	if f.Key == "" && temp.CustomField != "" {
-		f.Key = temp.CustomField
-	}
-
-	return nil
-}
-
 // Fields represents the content fields of a JIRA issue
 type Fields struct {
 	Summary    string     `json:"summary"`
@@ -58,9 +63,14 @@ type Fields struct {
 	StoryPoint float64    `json:"storypoints"` // Custom field
 	Priority   Priority   `json:"priority"`
 	Status     Status     `json:"status"`
+	Sprint     string     `json:"sprint,omitempty"` // Custom field
 	Epic       *Epic      `json:"epic,omitempty"`
 	Feature    *Feature   `json:"feature,omitempty"`
 	DueDate    *time.Time `json:"duedate,omitempty"`
+
+	// custom holds the customfield_* values until the field IDs of the
+	// instance are known, see resolve
+	custom map[string]json.RawMessage
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for Fields
@@ -68,8 +78,7 @@ func (f *Fields) UnmarshalJSON(data []byte) error {
 	type FieldsAlias Fields
 	type FieldsTemp struct {
 		*FieldsAlias
-		CustomField float64 `json:"customfield_12310243"`
-		DueDate     string  `json:"duedate"`
+		DueDate string `json:"duedate"`
 	}
 
 	temp := &FieldsTemp{FieldsAlias: (*FieldsAlias)(f)}
@@ -77,10 +86,12 @@ func (f *Fields) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	// Map the custom field to StoryPoint if StoryPoint isn't already set
-	if f.StoryPoint == 0 && temp.CustomField != 0 {
-		f.StoryPoint = temp.CustomField
+	custom, err := customFields(data)
+	if err != nil {
+		return err
 	}
+	f.custom = custom
+
 	// Parse DueDate if it's not empty
 	if temp.DueDate != "" {
 		// Try different date formats
@@ -101,6 +112,26 @@ func (f *Fields) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// resolve fills in the fields kept in custom fields, whose IDs differ
+// between instances
+func (f *Fields) resolve(r *FieldRegistry) {
+	if value := customValue(f.custom, r.ID(FieldStoryPoints)); value != nil && f.StoryPoint == 0 {
+		var points float64
+		if err := json.Unmarshal(value, &points); err == nil {
+			f.StoryPoint = points
+		}
+	}
+	// The Agile API returns the epic itself, the platform API only its key
+	if value := customValue(f.custom, r.ID(FieldEpicLink)); value != nil && f.Epic == nil {
+		if key := decodeIssueKey(value); key != "" {
+			f.Epic = &Epic{Key: key}
+		}
+	}
+	if value := customValue(f.custom, r.ID(FieldSprint)); value != nil && f.Sprint == "" {
+		f.Sprint = decodeSprintName(value)
+	}
+}
+
 // IsOverdue returns true if the issue is past its due date
 func (f Fields) IsOverdue() bool {
 	if f.DueDate == nil {
@@ -127,8 +158,37 @@ func (f Fields) DaysUntilDue() int {
 	return days
 }
 
+// Assignee is the user an issue is assigned to. Name is the username on
+// Server and Data Center; Jira Cloud has no usernames, so the display name
+// is used there instead.
 type Assignee struct {
 	Name string `json:"name"`
+
+	accountID string
+}
+
+// AccountID returns the Cloud account ID of the assignee, if any
+func (a Assignee) AccountID() string {
+	return a.accountID
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Assignee
+func (a *Assignee) UnmarshalJSON(data []byte) error {
+	var user User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return err
+	}
+
+	a.accountID = user.AccountID
+	switch {
+	case user.Name != "":
+		a.Name = user.Name
+	case user.DisplayName != "":
+		a.Name = user.DisplayName
+	default:
+		a.Name = user.AccountID
+	}
+	return nil
 }
 
 type Priority struct {
@@ -150,7 +210,14 @@ type Issue struct {
 }
 
 type JiraResponse struct {
-	Issues []Issue `json:"issues"`
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+	Issues     []Issue `json:"issues"`
+
+	// NextPageToken and IsLast page the search/jql endpoint of Jira Cloud
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	IsLast        bool   `json:"isLast,omitempty"`
 }
 
 // Sprint state type
@@ -301,10 +368,37 @@ type TransitionResponse struct {
 type Transition struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
+	// To is the status the transition leads to
+	To Status `json:"to"`
+	// Fields are the fields on the screen of the transition
+	Fields map[string]EditField `json:"fields,omitempty"`
 }
 
 type UpdateTransition struct {
 	Transition struct {
 		ID string `json:"id"`
 	} `json:"transition"`
+	Fields map[string]any `json:"fields,omitempty"`
+	Update map[string]any `json:"update,omitempty"`
+}
+
+// timeFormats are the layouts of the timestamps Jira returns
+var timeFormats = []string{
+	"2006-01-02T15:04:05.999-0700",
+	"2006-01-02T15:04:05.000Z",
+	"2006-01-02T15:04:05Z07:00",
+}
+
+// parseTime parses a timestamp returned by Jira, nil when it is empty or
+// in an unknown format
+func parseTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, value); err == nil {
+			return &t
+		}
+	}
+	return nil
 }
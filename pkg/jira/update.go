@@ -0,0 +1,281 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IssueUpdate describes changes to the fields of an issue. Fields left
+// empty are not changed.
+type IssueUpdate struct {
+	Summary string
+	// Assignee is a username, account ID, email address or display name
+	Assignee string
+	// Unassign removes the assignee of the issue
+	Unassign     bool
+	Priority     string
+	AddLabels    []string
+	RemoveLabels []string
+	// Components replaces the components of the issue
+	Components []string
+	// DueDate is a date in the form 2006-01-02
+	DueDate     string
+	StoryPoints *float64
+	// Fields holds further fields by ID, name or logical name, converted
+	// like the fields of NewIssue
+	Fields map[string]any
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u IssueUpdate) IsEmpty() bool {
+	return u.Summary == "" && u.Assignee == "" && !u.Unassign && u.Priority == "" &&
+		len(u.AddLabels) == 0 && len(u.RemoveLabels) == 0 && len(u.Components) == 0 &&
+		u.DueDate == "" && u.StoryPoints == nil && len(u.Fields) == 0
+}
+
+// EditMeta describes the fields of an issue that can be edited, which are
+// the fields on the edit screen of its project and issue type
+type EditMeta struct {
+	Fields map[string]EditField `json:"fields"`
+}
+
+// EditField describes a field that can be set when editing or
+// transitioning an issue, and the values it accepts
+type EditField struct {
+	Name            string         `json:"name"`
+	Required        bool           `json:"required"`
+	HasDefaultValue bool           `json:"hasDefaultValue,omitempty"`
+	Schema          FieldSchema    `json:"schema"`
+	Operations      []string       `json:"operations"`
+	AllowedValues   []AllowedValue `json:"allowedValues,omitempty"`
+}
+
+// AllowedValue is one of the values a field is restricted to, such as an
+// option or a priority
+type AllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// label returns what the allowed value is called
+func (v AllowedValue) label() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return v.Value
+}
+
+// GetEditMeta returns the fields of an issue that can be edited
+func (c *Client) GetEditMeta(ctx context.Context, issueKey string) (EditMeta, error) {
+	var meta EditMeta
+	if err := c.makeGetRequest(ctx, c.apiURL("issue/%s/editmeta", issueKey), &meta); err != nil {
+		return EditMeta{}, fmt.Errorf("error fetching editable fields of issue %s: %w", issueKey, err)
+	}
+	return meta, nil
+}
+
+// registry returns a field registry of the editable fields, so names are
+// resolved and values encoded the way they are on issue creation
+func (m EditMeta) registry(overrides map[string]string) *FieldRegistry {
+	var fields []Field
+	for id, field := range m.Fields {
+		fields = append(fields, Field{ID: id, Name: field.Name, Custom: strings.HasPrefix(id, "customfield_"), Schema: field.Schema})
+	}
+	// Sort so discovery does not depend on map order
+	sort.Slice(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+	return NewFieldRegistry(fields, overrides)
+}
+
+// UpdateIssueFields changes the fields of an issue. The changes are checked
+// against the edit metadata of the issue first, so fields that cannot be
+// edited and values a field does not allow are reported before anything is
+// sent.
+func (c *Client) UpdateIssueFields(ctx context.Context, issueKey string, update IssueUpdate) error {
+	if update.IsEmpty() {
+		return errors.New("nothing to update")
+	}
+	meta, err := c.GetEditMeta(ctx, issueKey)
+	if err != nil {
+		return err
+	}
+	editable := meta.registry(c.fieldIDs)
+
+	fields := map[string]any{}
+	if update.Summary != "" {
+		fields["summary"] = update.Summary
+	}
+	switch {
+	case update.Unassign:
+		fields["assignee"] = nil
+	case update.Assignee != "":
+		user, err := c.FindUser(ctx, update.Assignee)
+		if err != nil {
+			return fmt.Errorf("error finding assignee: %w", err)
+		}
+		fields["assignee"] = c.UserReference(ctx, user)
+	}
+	if update.Priority != "" {
+		fields["priority"] = map[string]string{"name": update.Priority}
+	}
+	if len(update.Components) > 0 {
+		components := make([]map[string]string, len(update.Components))
+		for i, name := range update.Components {
+			components[i] = map[string]string{"name": name}
+		}
+		fields["components"] = components
+	}
+	if update.DueDate != "" {
+		if _, err := time.Parse(time.DateOnly, update.DueDate); err != nil {
+			return fmt.Errorf("invalid due date %q: expected YYYY-MM-DD", update.DueDate)
+		}
+		fields["duedate"] = update.DueDate
+	}
+	if update.StoryPoints != nil {
+		id := editable.ID(FieldStoryPoints)
+		if id == "" {
+			return fmt.Errorf("story points cannot be edited on issue %s", issueKey)
+		}
+		fields[id] = *update.StoryPoints
+	}
+
+	for name := range update.Fields {
+		id, ok := editable.Resolve(name)
+		if _, editing := meta.Fields[id]; ok && editing {
+			continue
+		}
+		// Configured field IDs resolve even when the field is not editable
+		if _, known := c.fieldRegistry(ctx).Resolve(name); ok || known {
+			return fmt.Errorf("field %s cannot be edited on issue %s", name, issueKey)
+		}
+		return fmt.Errorf("unknown field %q", name)
+	}
+	custom, err := c.encodeFields(ctx, editable, update.Fields)
+	if err != nil {
+		return err
+	}
+	for id, value := range custom {
+		fields[id] = value
+	}
+
+	// Labels are added and removed rather than replaced, so labels set by
+	// others in the meantime are kept
+	var labels []map[string]string
+	for _, label := range update.AddLabels {
+		labels = append(labels, map[string]string{"add": label})
+	}
+	for _, label := range update.RemoveLabels {
+		labels = append(labels, map[string]string{"remove": label})
+	}
+
+	if err := meta.validate(fields, labels); err != nil {
+		return fmt.Errorf("cannot update issue %s: %w", issueKey, err)
+	}
+
+	payload := map[string]any{}
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
+	if len(labels) > 0 {
+		payload["update"] = map[string]any{"labels": labels}
+	}
+	if err := c.makePutRequest(ctx, c.apiURL("issue/%s", issueKey), payload, nil); err != nil {
+		return fmt.Errorf("error updating issue %s: %w", issueKey, err)
+	}
+	return nil
+}
+
+// validate checks fields to set and label operations against the edit
+// metadata, reporting every problem found
+func (m EditMeta) validate(fields map[string]any, labels []map[string]string) error {
+	var problems []string
+	check := func(id, operation string) (EditField, bool) {
+		field, ok := m.Fields[id]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("field %s cannot be edited", id))
+		case len(field.Operations) > 0 && !slices.Contains(field.Operations, operation):
+			problems = append(problems, fmt.Sprintf("field %s does not support %s", field.Name, operation))
+			ok = false
+		}
+		return field, ok
+	}
+
+	ids := make([]string, 0, len(fields))
+	for id := range fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		field, ok := check(id, "set")
+		if !ok {
+			continue
+		}
+		if fields[id] == nil && field.Required {
+			problems = append(problems, fmt.Sprintf("field %s is required", field.Name))
+		}
+		if problem := field.checkAllowed(fields[id]); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	for _, label := range labels {
+		for operation := range label {
+			check("labels", operation)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(slices.Compact(problems), "; "))
+	}
+	return nil
+}
+
+// checkAllowed returns a problem when value, or an item of it, is not one
+// of the allowed values of the field. Names matching an allowed value in
+// another case are replaced with the allowed spelling.
+func (f EditField) checkAllowed(value any) string {
+	if len(f.AllowedValues) == 0 {
+		return ""
+	}
+	items := []any{value}
+	switch v := value.(type) {
+	case []map[string]string:
+		items = nil
+		for _, item := range v {
+			items = append(items, item)
+		}
+	case []any:
+		items = v
+	}
+
+	for _, item := range items {
+		ref, ok := item.(map[string]string)
+		if !ok {
+			continue
+		}
+		i := slices.IndexFunc(f.AllowedValues, func(v AllowedValue) bool {
+			return (ref["id"] != "" && ref["id"] == v.ID) ||
+				(ref["name"] != "" && strings.EqualFold(ref["name"], v.Name)) ||
+				(ref["value"] != "" && strings.EqualFold(ref["value"], v.Value))
+		})
+		if i < 0 {
+			var names []string
+			for _, v := range f.AllowedValues {
+				names = append(names, v.label())
+			}
+			return fmt.Sprintf("%s is not a valid %s, expected one of %s", ref["name"]+ref["value"]+ref["id"], f.Name, strings.Join(names, ", "))
+		}
+		if _, ok := ref["name"]; ok {
+			ref["name"] = f.AllowedValues[i].Name
+		}
+		if _, ok := ref["value"]; ok {
+			ref["value"] = f.AllowedValues[i].Value
+		}
+	}
+	return ""
+}
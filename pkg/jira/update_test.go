@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testEditMeta = `{"fields": {
+	"summary": {"name": "Summary", "required": true, "schema": {"type": "string", "system": "summary"}, "operations": ["set"]},
+	"assignee": {"name": "Assignee", "required": false, "schema": {"type": "user", "system": "assignee"}, "operations": ["set"]},
+	"priority": {"name": "Priority", "required": false, "schema": {"type": "priority", "system": "priority"}, "operations": ["set"],
+		"allowedValues": [{"id": "1", "name": "High"}, {"id": "2", "name": "Low"}]},
+	"labels": {"name": "Labels", "required": false, "schema": {"type": "array", "items": "string", "system": "labels"}, "operations": ["add", "set", "remove"]},
+	"duedate": {"name": "Due Date", "required": false, "schema": {"type": "date", "system": "duedate"}, "operations": ["set"]},
+	"customfield_12310243": {"name": "Story Points", "required": false, "schema": {"type": "number", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:float"}, "operations": ["set"]},
+	"customfield_10200": {"name": "Severity", "required": false, "schema": {"type": "option"}, "operations": ["set"],
+		"allowedValues": [{"id": "10", "value": "Major"}, {"id": "11", "value": "Minor"}]}
+}}`
+
+// newUpdateTestServer serves the edit metadata of TEST-1 and records the
+// payload of the update
+func newUpdateTestServer(t *testing.T, updated *map[string]any) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/TEST-1/editmeta":
+			_, _ = w.Write([]byte(testEditMeta))
+		case "/rest/api/2/user/search":
+			_ = json.NewEncoder(w).Encode([]User{{Name: "jdoe", DisplayName: "Jane Doe"}})
+		case "/rest/api/2/issue/TEST-1":
+			require.Equal(t, http.MethodPut, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(updated))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testServerInfo, testFieldIDs)
+}
+
+func TestUpdateIssueFields(t *testing.T) {
+	var payload map[string]any
+	client := newUpdateTestServer(t, &payload)
+
+	points := 5.0
+	err := client.UpdateIssueFields(context.Background(), "TEST-1", IssueUpdate{
+		Summary:      "New summary",
+		Assignee:     "jane",
+		Priority:     "high",
+		AddLabels:    []string{"reports"},
+		RemoveLabels: []string{"triage"},
+		DueDate:      "2025-07-01",
+		StoryPoints:  &points,
+		Fields:       map[string]any{"severity": "minor"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"fields": map[string]any{
+			"summary":              "New summary",
+			"assignee":             map[string]any{"name": "jdoe"},
+			"priority":             map[string]any{"name": "High"},
+			"duedate":              "2025-07-01",
+			"customfield_12310243": 5.0,
+			"customfield_10200":    map[string]any{"value": "Minor"},
+		},
+		"update": map[string]any{
+			"labels": []any{map[string]any{"add": "reports"}, map[string]any{"remove": "triage"}},
+		},
+	}, payload)
+}
+
+func TestUpdateIssueFieldsUnassign(t *testing.T) {
+	var payload map[string]any
+	client := newUpdateTestServer(t, &payload)
+
+	require.NoError(t, client.UpdateIssueFields(context.Background(), "TEST-1", IssueUpdate{Unassign: true}))
+	assert.Equal(t, map[string]any{"fields": map[string]any{"assignee": nil}}, payload)
+}
+
+func TestUpdateIssueFieldsValidation(t *testing.T) {
+	var payload map[string]any
+	client := newUpdateTestServer(t, &payload)
+
+	tests := []struct {
+		name   string
+		update IssueUpdate
+		err    string
+	}{
+		{name: "nothing", update: IssueUpdate{}, err: "nothing to update"},
+		{name: "not allowed", update: IssueUpdate{Priority: "Blocker"}, err: "Blocker is not a valid Priority, expected one of High, Low"},
+		{name: "option not allowed", update: IssueUpdate{Fields: map[string]any{"Severity": "Critical"}}, err: "Critical is not a valid Severity, expected one of Major, Minor"},
+		{name: "not on edit screen", update: IssueUpdate{Components: []string{"CLI"}}, err: "cannot update issue TEST-1: field components cannot be edited"},
+		{name: "field not on edit screen", update: IssueUpdate{Fields: map[string]any{"epic_link": "TEST-2"}}, err: "field epic_link cannot be edited on issue TEST-1"},
+		{name: "unknown field", update: IssueUpdate{Fields: map[string]any{"Colour": "red"}}, err: `unknown field "Colour"`},
+		{name: "due date", update: IssueUpdate{DueDate: "01/07/2025"}, err: "expected YYYY-MM-DD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload = nil
+			err := client.UpdateIssueFields(context.Background(), "TEST-1", tt.update)
+			assert.ErrorContains(t, err, tt.err)
+			assert.Nil(t, payload, "nothing is sent")
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package jira
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MarkdownToWiki converts Markdown into Jira wiki markup, the rich text
+// format of REST API v2. It understands the same subset as MarkdownToADF.
+func MarkdownToWiki(markdown string) string {
+	return ADFToWiki(MarkdownToADF(markdown))
+}
+
+// ADFToWiki renders an ADF document as Jira wiki markup
+func ADFToWiki(doc *ADFNode) string {
+	if doc == nil {
+		return ""
+	}
+	if doc.Type != "doc" {
+		return strings.TrimSpace(wikiBlocks([]ADFNode{*doc}, ""))
+	}
+	return strings.TrimSpace(wikiBlocks(doc.Content, ""))
+}
+
+// wikiBlocks renders block nodes separated by blank lines. Lists nested in
+// another list continue its marker, given as bullets.
+func wikiBlocks(nodes []ADFNode, bullets string) string {
+	var parts []string
+	for _, node := range nodes {
+		if block := wikiBlock(node, bullets); block != "" {
+			parts = append(parts, block)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func wikiBlock(n ADFNode, bullets string) string {
+	switch n.Type {
+	case "paragraph":
+		return wikiInline(n.Content)
+	case "heading":
+		level, _ := strconv.Atoi(n.attr("level"))
+		return fmt.Sprintf("h%d. %s", min(max(level, 1), 6), wikiInline(n.Content))
+	case "bulletList", "orderedList":
+		return wikiList(n, bullets)
+	case "codeBlock":
+		var code strings.Builder
+		for _, child := range n.Content {
+			code.WriteString(child.Text)
+		}
+		macro := "{code}"
+		if language := n.attr("language"); language != "" {
+			macro = "{code:" + language + "}"
+		}
+		return macro + "\n" + code.String() + "\n{code}"
+	case "blockquote", "panel":
+		return "{quote}\n" + wikiBlocks(n.Content, "") + "\n{quote}"
+	case "rule":
+		return "----"
+	case "table":
+		return wikiTable(n)
+	case "mediaSingle", "mediaGroup", "media":
+		return ""
+	default:
+		if len(n.Content) > 0 && isBlockNode(n.Content[0]) {
+			return wikiBlocks(n.Content, bullets)
+		}
+		return wikiInline([]ADFNode{n})
+	}
+}
+
+// wikiList renders a list with one marker character per level of nesting,
+// e.g. "*#" for a numbered list inside a bullet list
+func wikiList(n ADFNode, bullets string) string {
+	marker := "*"
+	if n.Type == "orderedList" {
+		marker = "#"
+	}
+	bullets += marker
+
+	var lines []string
+	for _, item := range n.Content {
+		var text []string
+		for _, child := range item.Content {
+			switch child.Type {
+			case "bulletList", "orderedList":
+				if text != nil {
+					lines = append(lines, bullets+" "+strings.Join(text, "\n"))
+					text = nil
+				}
+				lines = append(lines, wikiList(child, bullets))
+			default:
+				if block := wikiBlock(child, bullets); block != "" {
+					text = append(text, block)
+				}
+			}
+		}
+		if text != nil {
+			lines = append(lines, bullets+" "+strings.Join(text, "\n"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wikiTable renders a table, header cells between double bars
+func wikiTable(n ADFNode) string {
+	var lines []string
+	for _, row := range n.Content {
+		var line strings.Builder
+		separator := "|"
+		for _, cell := range row.Content {
+			separator = "|"
+			if cell.Type == "tableHeader" {
+				separator = "||"
+			}
+			text := strings.ReplaceAll(wikiBlocks(cell.Content, ""), "\n", " ")
+			line.WriteString(separator + strings.ReplaceAll(text, "|", `\|`))
+		}
+		line.WriteString(separator)
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wikiInline renders inline nodes such as text, mentions and hard breaks
+func wikiInline(nodes []ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(wikiText(n))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString("[~" + strings.TrimPrefix(n.attr("text"), "@") + "]")
+		case "emoji":
+			if text := n.attr("text"); text != "" {
+				b.WriteString(text)
+			} else {
+				b.WriteString(n.attr("shortName"))
+			}
+		case "inlineCard", "blockCard":
+			b.WriteString("[" + n.attr("url") + "]")
+		default:
+			b.WriteString(wikiInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+// wikiEscaper escapes the characters that start macros and links in text
+var wikiEscaper = strings.NewReplacer(`{`, `\{`, `}`, `\}`, `[`, `\[`, `]`, `\]`)
+
+// wikiText renders a text node with its marks
+func wikiText(n ADFNode) string {
+	// Jira interprets macros and links even inside monospaced text
+	text := wikiEscaper.Replace(n.Text)
+	var href string
+	for _, mark := range n.Marks {
+		switch mark.Type {
+		case "link":
+			href = fmt.Sprint(mark.Attrs["href"])
+		case "code":
+			text = "{{" + text + "}}"
+		case "strong":
+			text = "*" + text + "*"
+		case "em":
+			text = "_" + text + "_"
+		case "strike":
+			text = "-" + text + "-"
+		}
+	}
+
+	switch {
+	case href == "":
+		return text
+	case n.Text == href:
+		return "[" + href + "]"
+	default:
+		return "[" + text + "|" + href + "]"
+	}
+}
@@ -0,0 +1,32 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownToWiki(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"paragraphs", "first\nline\n\nsecond", "first\nline\n\nsecond"},
+		{"heading", "## Title", "h2. Title"},
+		{"emphasis", "**bold** *em* ~~strike~~ `code`", "*bold* _em_ -strike- {{code}}"},
+		{"escapes", "use {braces} and [brackets]", `use \{braces\} and \[brackets\]`},
+		{"links", "[docs](https://example.com) <https://example.org>", "[docs|https://example.com] [https://example.org]"},
+		{"code block", "```go\nx := 1\n```", "{code:go}\nx := 1\n{code}"},
+		{"blockquote", "> quoted", "{quote}\nquoted\n{quote}"},
+		{"rule", "---", "----"},
+		{"nested list", "- parent\n  1. child\n- sibling", "* parent\n*# child\n* sibling"},
+		{"table", "| a | b |\n|---|---|\n| 1 | 2 |", "||a||b||\n|1|2|"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MarkdownToWiki(tt.markdown))
+		})
+	}
+	assert.Empty(t, ADFToWiki(nil))
+}
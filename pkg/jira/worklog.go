@@ -0,0 +1,117 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"time"
+)
+
+// worklogTimeFormat is the layout Jira expects for the start of a worklog
+const worklogTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+// Worklog is time logged on an issue
+type Worklog struct {
+	ID               string      `json:"id"`
+	IssueID          string      `json:"issueId"`
+	Author           User        `json:"author"`
+	Comment          RichText    `json:"comment"`
+	Started          *time.Time  `json:"started,omitempty"`
+	TimeSpent        string      `json:"timeSpent"`
+	TimeSpentSeconds int         `json:"timeSpentSeconds"`
+	Visibility       *Visibility `json:"visibility,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, parsing Jira's timestamps
+func (w *Worklog) UnmarshalJSON(data []byte) error {
+	type worklog Worklog
+	var temp struct {
+		worklog
+		Started string `json:"started"`
+	}
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	*w = Worklog(temp.worklog)
+	w.Started = parseTime(temp.Started)
+	return nil
+}
+
+// Duration returns the time spent
+func (w Worklog) Duration() time.Duration {
+	return time.Duration(w.TimeSpentSeconds) * time.Second
+}
+
+// WorklogResponse is a page of the worklogs of an issue
+type WorklogResponse struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Worklogs   []Worklog `json:"worklogs"`
+}
+
+// NewWorklog is time to log on an issue
+type NewWorklog struct {
+	// TimeSpent is rounded to the minute, the precision of Jira
+	TimeSpent time.Duration
+	// Started is when the work started, the zero time meaning now
+	Started time.Time
+	// Comment is Markdown, converted to the rich text format of the API
+	// version. It is optional.
+	Comment string
+	// Visibility restricts the worklog, nil makes it visible to everyone
+	// who can see the issue
+	Visibility *Visibility
+}
+
+// FetchWorklogs returns the worklogs of an issue, oldest first. A limit of
+// zero or less returns every worklog.
+func (c *Client) FetchWorklogs(ctx context.Context, issueKey string, limit int) ([]Worklog, error) {
+	return collect(c.Worklogs(ctx, issueKey), limit)
+}
+
+// Worklogs returns an iterator over the worklogs of an issue, oldest first.
+// Pages are requested from Jira as the iterator advances.
+func (c *Client) Worklogs(ctx context.Context, issueKey string) iter.Seq2[Worklog, error] {
+	return paginate(0, func(startAt int) ([]Worklog, pageInfo, error) {
+		var response WorklogResponse
+		url := c.apiURL("issue/%s/worklog?startAt=%d", issueKey, startAt)
+		if err := c.makeGetRequest(ctx, url, &response); err != nil {
+			return nil, pageInfo{}, fmt.Errorf("error fetching worklogs of issue %s: %w", issueKey, err)
+		}
+		return response.Worklogs, response.pageInfo(), nil
+	})
+}
+
+// AddWorklog logs time on an issue and returns the new worklog
+func (c *Client) AddWorklog(ctx context.Context, issueKey string, worklog NewWorklog) (Worklog, error) {
+	spent := worklog.TimeSpent.Round(time.Minute)
+	if spent < time.Minute {
+		return Worklog{}, fmt.Errorf("cannot log %s on issue %s: at least a minute is required", worklog.TimeSpent, issueKey)
+	}
+	started := worklog.Started
+	if started.IsZero() {
+		started = time.Now()
+	}
+
+	payload := map[string]any{
+		"timeSpentSeconds": int(spent.Seconds()),
+		"started":          started.Format(worklogTimeFormat),
+	}
+	if worklog.Comment != "" {
+		payload["comment"] = c.commentText(worklog.Comment)
+	}
+	if worklog.Visibility != nil {
+		if err := worklog.Visibility.validate(); err != nil {
+			return Worklog{}, err
+		}
+		payload["visibility"] = worklog.Visibility
+	}
+
+	var created Worklog
+	if err := c.makePostRequest(ctx, c.apiURL("issue/%s/worklog", issueKey), payload, &created); err != nil {
+		return Worklog{}, fmt.Errorf("error logging work on issue %s: %w", issueKey, err)
+	}
+	return created, nil
+}
@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchWorklogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/api/2/issue/TEST-1/worklog", r.URL.Path)
+		_, _ = w.Write([]byte(`{"startAt": 0, "maxResults": 2, "total": 2, "worklogs": [
+			{"id": "200", "issueId": "10001", "author": {"name": "jdoe", "displayName": "Jane Doe"},
+				"comment": "Review", "started": "2025-06-02T09:00:00.000+0200", "timeSpent": "1h 30m", "timeSpentSeconds": 5400},
+			{"id": "201", "issueId": "10001", "author": {"name": "bob", "displayName": "Bob"},
+				"started": "2025-06-03T14:00:00.000+0000", "timeSpent": "1d", "timeSpentSeconds": 28800,
+				"visibility": {"type": "group", "value": "jira-developers"}}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, testAuth, WithHTTPClient(server.Client()), testServerInfo)
+
+	worklogs, err := client.FetchWorklogs(context.Background(), "TEST-1", 0)
+	require.NoError(t, err)
+	require.Len(t, worklogs, 2)
+	assert.Equal(t, "Jane Doe", worklogs[0].Author.DisplayName)
+	assert.Equal(t, RichText("Review"), worklogs[0].Comment)
+	assert.Equal(t, 90*time.Minute, worklogs[0].Duration())
+	require.NotNil(t, worklogs[0].Started)
+	assert.True(t, worklogs[0].Started.Equal(time.Date(2025, 6, 2, 7, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "group jira-developers", worklogs[1].Visibility.String())
+}
+
+func TestAddWorklog(t *testing.T) {
+	var url string
+	var payload any
+	client := NewClient(testBaseURL, testAuth,
+		WithPostRequestFunc(func(ctx context.Context, reqURL string, body any, target any) error {
+			url, payload = reqURL, body
+			return json.Unmarshal([]byte(`{"id": "200"}`), target)
+		}))
+
+	started := time.Date(2025, 6, 2, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	worklog, err := client.AddWorklog(context.Background(), "TEST-1", NewWorklog{
+		TimeSpent:  90*time.Minute + 10*time.Second,
+		Started:    started,
+		Comment:    "Reviewed **PR 12**",
+		Visibility: &Visibility{Type: VisibilityRole, Value: "Developers"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "200", worklog.ID)
+	assert.Equal(t, testBaseURL+"/rest/api/2/issue/TEST-1/worklog", url)
+	assert.Equal(t, map[string]any{
+		"timeSpentSeconds": 5400,
+		"started":          "2025-06-02T09:00:00.000+0200",
+		"comment":          "Reviewed *PR 12*",
+		"visibility":       &Visibility{Type: VisibilityRole, Value: "Developers"},
+	}, payload)
+}
+
+func TestAddWorklogErrors(t *testing.T) {
+	client := NewClient(testBaseURL, testAuth,
+		WithPostRequestFunc(func(ctx context.Context, url string, body any, target any) error {
+			return fmt.Errorf("400 Bad Request")
+		}))
+
+	_, err := client.AddWorklog(context.Background(), "TEST-1", NewWorklog{TimeSpent: 20 * time.Second})
+	assert.EqualError(t, err, "cannot log 20s on issue TEST-1: at least a minute is required")
+
+	_, err = client.AddWorklog(context.Background(), "TEST-1", NewWorklog{TimeSpent: time.Hour, Visibility: &Visibility{Type: VisibilityGroup}})
+	assert.EqualError(t, err, "a group is required to restrict visibility")
+
+	_, err = client.AddWorklog(context.Background(), "TEST-1", NewWorklog{TimeSpent: time.Hour})
+	assert.EqualError(t, err, "error logging work on issue TEST-1: 400 Bad Request")
+}